@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"vadilatorgolang/package/config"
+	"vadilatorgolang/package/logger"
+	"vadilatorgolang/package/logsearch"
+)
+
+// runLogs xử lý lệnh con "logs": gộp các file log theo mức độ trong
+// -log-dir theo thứ tự thời gian, lọc và in ra dạng bảng hoặc JSON lines
+func runLogs(args []string) int {
+	loader := config.NewLoader("logs")
+	fs := loader.FlagSet
+	level := fs.String("level", "trace", "chỉ hiện bản ghi từ mức này trở lên (trace, debug, info, warn, error)")
+	since := fs.String("since", "", "từ thời điểm (RFC 3339, \"2006-01-02 15:04:05\", \"2006-01-02\" hoặc khoảng lùi như 15m)")
+	until := fs.String("until", "", "tới thời điểm, không bao gồm (cùng định dạng với -since)")
+	path := fs.String("path", "", "đường dẫn request, hỗ trợ mẫu như /user/*")
+	reqID := fs.String("request-id", "", "request ID")
+	text := fs.String("grep", "", "chuỗi cần tìm (không phân biệt hoa thường)")
+	tail := fs.Int("tail", 0, "chỉ hiện N bản ghi khớp cuối cùng (0 = tất cả)")
+	var follow bool
+	fs.BoolVar(&follow, "follow", false, "tiếp tục chờ và in bản ghi mới")
+	fs.BoolVar(&follow, "f", false, "viết tắt của -follow")
+	output := fs.String("output", "table", "định dạng in ra: table hoặc json")
+	color := fs.String("color", "auto", "tô màu mức độ: auto, always hoặc never")
+
+	cfg, err := loader.Load(args)
+	if errors.Is(err, flag.ErrHelp) {
+		return 0
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Không thể nạp cấu hình:", err)
+		return 2
+	}
+
+	opts := logsearch.Options{Dir: cfg.Log.Dir, Tail: *tail, Follow: follow}
+	now := time.Now()
+	if opts.Filter.MinLevel, err = logger.ParseLevel(*level); err == nil {
+		if opts.Filter.Since, err = parseTimeArg(*since, now); err == nil {
+			opts.Filter.Until, err = parseTimeArg(*until, now)
+		}
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	opts.Filter.Path, opts.Filter.RequestID, opts.Filter.Text = *path, *reqID, *text
+
+	var print func(*logsearch.Entry) error
+	switch *output {
+	case "table":
+		useColor := *color == "always"
+		if *color == "auto" {
+			useColor = isTerminal(os.Stdout) && os.Getenv("NO_COLOR") == ""
+		}
+		print = logsearch.NewTablePrinter(os.Stdout, useColor).Print
+	case "json":
+		print = logsearch.NewJSONPrinter(os.Stdout).Print
+	default:
+		fmt.Fprintf(os.Stderr, "-output không hợp lệ: %q (table hoặc json)\n", *output)
+		return 2
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := logsearch.Search(ctx, opts, print); err != nil {
+		fmt.Fprintln(os.Stderr, "Lỗi đọc log:", err)
+		return 1
+	}
+	return 0
+}
+
+// parseTimeArg đọc thời điểm tuyệt đối (giờ địa phương nếu không ghi múi giờ)
+// hoặc khoảng lùi tính từ now; chuỗi rỗng trả về thời điểm zero
+func parseTimeArg(s string, now time.Time) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04:05", "2006-01-02T15:04:05", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("thời điểm không hợp lệ: %q", s)
+}
+
+func isTerminal(f *os.File) bool {
+	st, err := f.Stat()
+	return err == nil && st.Mode()&os.ModeCharDevice != 0
+}
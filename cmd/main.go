@@ -1,64 +1,40 @@
 package main
 
 import (
-	"net/http"
-
-	"vadilatorgolang/internal/user"
-	"vadilatorgolang/package/database"
-	"vadilatorgolang/package/logger"
-	"vadilatorgolang/package/server"
-	customValidator "vadilatorgolang/package/validator"
+	"fmt"
+	"os"
 )
 
-func main() {
-	// 0. Khởi tạo Logger (5 cấp độ)
-	logger.InitLoggers()
-	logger.InfoLogger.Println("Khởi tạo logger hoàn tất.")
+const usage = `Cách dùng:
+  %[1]s [serve] [flags]                  chạy HTTP server (mặc định)
+  %[1]s migrate up|down|status|redo|create [flags] [args]
+                                          quản lý schema database
+  %[1]s logs [flags]                     gộp, lọc và theo dõi các file log
 
-	// 1. Kết nối Database
-	db, err := database.ConnectDb()
-	if err != nil {
-		logger.ErrorLogger.Println("Không thể kết nối tới database:", err)
-		return
-	}
-	_, err = database.ConnectDb()
-	if err != nil {
-		logger.ErrorLogger.Println("Không thể kết nối tới database:", err)
-		return
-	}
-	_, err = database.ConnectDb()
-	if err != nil {
-		logger.ErrorLogger.Println("Không thể kết nối tới database:", err)
-		return
-	}
-	_, err = database.ConnectDb()
-	if err != nil {
-		logger.ErrorLogger.Println("Không thể kết nối tới database:", err)
-		return
-	}
-	defer db.Close()
-	logger.InfoLogger.Println("Kết nối database thành công.")
+Chạy "%[1]s <lệnh> -h" để xem danh sách flag.
+`
 
-	// 2. Đăng ký Custom Validator
-	customValidator.RegisterCustomValidations()
-	logger.DebugLogger.Println("Đã đăng ký custom validators.")
-
-	// 3. Khởi tạo các tầng: Repo → Controller → Handler
-	userRepo := user.NewUserRepo(db)
-	userCtrl := user.NewUserController(userRepo)
-	userHandler := user.NewUserHandler(userCtrl)
-	logger.TraceLogger.Println("Đã khởi tạo các dependency.")
-
-	// 4. Khởi tạo Router
-	router := server.NewRouter(userHandler)
-	logger.DebugLogger.Println("Đã khởi tạo router.")
-
-	// 5. Khởi động Server
-	port := ":8080"
-	logger.InfoLogger.Printf("Server đang chạy tại http://localhost%s", port)
+func main() {
+	args := os.Args[1:]
+	cmd := "serve"
+	if len(args) > 0 && args[0] != "" && args[0][0] != '-' {
+		cmd, args = args[0], args[1:]
+	}
 
-	err = http.ListenAndServe(port, router)
-	if err != nil {
-		logger.InfoLogger.Println("Lỗi khi khởi động server:", err)
+	var code int
+	switch cmd {
+	case "serve":
+		code = runServe(args)
+	case "migrate":
+		code = runMigrate(args)
+	case "logs":
+		code = runLogs(args)
+	case "help":
+		fmt.Printf(usage, os.Args[0])
+	default:
+		fmt.Fprintf(os.Stderr, "Lệnh không hợp lệ: %s\n\n", cmd)
+		fmt.Fprintf(os.Stderr, usage, os.Args[0])
+		code = 2
 	}
+	os.Exit(code)
 }
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"vadilatorgolang/package/config"
+	"vadilatorgolang/package/database"
+	"vadilatorgolang/package/migrate"
+)
+
+const migrateUsage = `Cách dùng: migrate <lệnh> [flags] [args]
+  up [N]        áp dụng N migration đang chờ (mặc định: tất cả)
+  down [N]      hoàn tác N migration gần nhất (mặc định: 1)
+  status        liệt kê trạng thái từng migration
+  redo          hoàn tác rồi áp dụng lại migration gần nhất
+  create NAME   tạo cặp file up/down mới trong -dir
+`
+
+// runMigrate xử lý lệnh con "migrate", trả về exit code của tiến trình
+func runMigrate(args []string) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "help" {
+		fmt.Print(migrateUsage)
+		return 0
+	}
+	sub, args := args[0], args[1:]
+
+	loader := config.NewLoader("migrate " + sub)
+	dir := loader.FlagSet.String("dir", migrate.DefaultDir, "thư mục chứa file migration (cho lệnh create)")
+	cfg, err := loader.Load(args)
+	if errors.Is(err, flag.ErrHelp) {
+		return 0
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Không thể nạp cấu hình:", err)
+		return 2
+	}
+	rest := loader.FlagSet.Args()
+
+	// create không cần kết nối database
+	if sub == "create" {
+		if len(rest) != 1 {
+			fmt.Fprintln(os.Stderr, "Thiếu tên migration: migrate create NAME")
+			return 2
+		}
+		up, down, err := migrate.Create(*dir, rest[0])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Không thể tạo migration:", err)
+			return 1
+		}
+		fmt.Printf("Đã tạo %s\nĐã tạo %s\n", up, down)
+		return 0
+	}
+
+	n := 0
+	if len(rest) > 0 {
+		if n, err = strconv.Atoi(rest[0]); err != nil || n <= 0 {
+			fmt.Fprintf(os.Stderr, "N phải là số nguyên dương: %s\n", rest[0])
+			return 2
+		}
+	}
+
+	connectCtx, cancel := context.WithTimeout(context.Background(), cfg.Database.ConnectRetry.AttemptTimeout)
+	db, err := database.ConnectDb(connectCtx, cfg.Database)
+	cancel()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Không thể kết nối tới database:", err)
+		return 1
+	}
+	defer db.Close()
+
+	migrator, err := migrate.New(db, migrate.Embedded())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Không thể nạp migration:", err)
+		return 1
+	}
+
+	ctx := context.Background()
+	switch sub {
+	case "up":
+		done, err := migrator.Up(ctx, n)
+		printMigrations("Đã áp dụng", done)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Lỗi migrate up:", err)
+			return 1
+		}
+		if len(done) == 0 {
+			fmt.Println("Không có migration nào đang chờ.")
+		}
+	case "down":
+		done, err := migrator.Down(ctx, n)
+		printMigrations("Đã hoàn tác", done)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Lỗi migrate down:", err)
+			return 1
+		}
+	case "redo":
+		mig, err := migrator.Redo(ctx)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Lỗi migrate redo:", err)
+			return 1
+		}
+		if mig != nil {
+			printMigrations("Đã chạy lại", []migrate.Migration{*mig})
+		}
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Lỗi migrate status:", err)
+			return 1
+		}
+		tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+		for _, s := range statuses {
+			state, at := "pending", ""
+			if s.Applied {
+				state, at = "applied", s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			if s.Modified {
+				state = "modified"
+			}
+			fmt.Fprintf(tw, "%04d\t%s\t%s\t%s\n", s.Version, s.Name, state, at)
+		}
+		tw.Flush()
+	default:
+		fmt.Fprintf(os.Stderr, "Lệnh migrate không hợp lệ: %s\n\n%s", sub, migrateUsage)
+		return 2
+	}
+	return 0
+}
+
+func printMigrations(action string, migrations []migrate.Migration) {
+	for _, m := range migrations {
+		fmt.Printf("%s %04d_%s\n", action, m.Version, m.Name)
+	}
+}
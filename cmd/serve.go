@@ -0,0 +1,236 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"vadilatorgolang/internal/audit"
+	"vadilatorgolang/internal/user"
+	"vadilatorgolang/package/admin"
+	"vadilatorgolang/package/config"
+	"vadilatorgolang/package/database"
+	"vadilatorgolang/package/health"
+	"vadilatorgolang/package/lifecycle"
+	"vadilatorgolang/package/logger"
+	"vadilatorgolang/package/metrics"
+	"vadilatorgolang/package/migrate"
+	"vadilatorgolang/package/ratelimit"
+	"vadilatorgolang/package/server"
+	"vadilatorgolang/package/tracing"
+	customValidator "vadilatorgolang/package/validator"
+)
+
+// runServe khởi động HTTP server, trả về exit code của tiến trình
+func runServe(args []string) int {
+	// 0. Nạp cấu hình (mặc định → file → env → flag)
+	loader := config.NewLoader("serve")
+	printConfig := loader.FlagSet.Bool("print-config", false, "in cấu hình hiệu lực (đã che secret) rồi thoát")
+	cfg, err := loader.Load(args)
+	if errors.Is(err, flag.ErrHelp) {
+		return 0
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Không thể nạp cấu hình:", err)
+		return 2
+	}
+	if *printConfig {
+		fmt.Print(cfg)
+		return 0
+	}
+
+	// 1. Khởi tạo Logger (5 cấp độ)
+	logger.InitLoggers(cfg.Log)
+	logger.InfoLogger.Println("Khởi tạo logger hoàn tất.")
+	logger.DebugLogger.Printf("Cấu hình hiệu lực:\n%s", cfg)
+
+	// Vòng đời: logger đăng ký đầu tiên nên được đóng cuối cùng
+	app := lifecycle.New(cfg.Server.ShutdownTimeout)
+	app.Logf = logger.InfoLogger.Printf
+	// SIGHUP: mở lại file log sau khi logrotate bên ngoài đổi tên file.
+	// SIGUSR1/SIGUSR2: tăng độ chi tiết / quay về mức log trong cấu hình.
+	stopReopen, stopLevelSignals := func() {}, func() {}
+	app.Append(lifecycle.Hook{
+		Name: "logger",
+		OnStart: func(ctx context.Context) error {
+			stopReopen = logger.ReopenOnSignal(syscall.SIGHUP)
+			stopLevelSignals = logger.LevelSignals()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			stopLevelSignals()
+			stopReopen()
+			return logger.Close()
+		},
+	})
+
+	// 2. Kết nối Database, thử lại nếu MySQL chưa sẵn sàng (Ctrl+C để dừng)
+	connectCtx, stopConnect := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	db, err := database.ConnectWithRetry(connectCtx, cfg.Database, func(attempt int, wait time.Duration, err error) {
+		logger.WarnLogger.Printf("Kết nối database thất bại (lần %d), thử lại sau %s: %v", attempt, wait.Round(time.Millisecond), err)
+	})
+	stopConnect()
+	if err != nil {
+		logger.ErrorLogger.Println("Không thể kết nối tới database:", err)
+		logger.Close()
+		return 1
+	}
+	app.Append(lifecycle.Hook{
+		Name:   "database",
+		OnStop: func(ctx context.Context) error { return db.Close() },
+	})
+	logger.InfoLogger.Println("Kết nối database thành công.")
+	metrics.Register(metrics.NewDBStatsCollector(db, cfg.Database.Name))
+
+	// abort dọn dẹp khi phải thoát trước khi app.Run được gọi
+	abort := func() {
+		db.Close()
+		logger.Close()
+	}
+
+	// 3. Kiểm tra migration
+	migrator, err := migrate.New(db, migrate.Embedded())
+	if err != nil {
+		logger.ErrorLogger.Println("Không thể nạp migration:", err)
+		abort()
+		return 1
+	}
+	pending, err := migrator.Pending(context.Background())
+	if err != nil {
+		logger.ErrorLogger.Println("Không thể kiểm tra migration:", err)
+		abort()
+		return 1
+	}
+	if len(pending) > 0 {
+		if cfg.Database.RequireMigrations {
+			logger.ErrorLogger.Printf("Còn %d migration chưa chạy, hãy chạy \"migrate up\" trước.", len(pending))
+			abort()
+			return 1
+		}
+		logger.WarnLogger.Printf("Còn %d migration chưa chạy.", len(pending))
+	}
+
+	// Health check cho /livez, /readyz, /healthz
+	checks := health.New(cfg.Health.CacheTTL, cfg.Health.Timeout)
+	checks.Logf = logger.WarnLogger.Printf
+	checks.Register(health.Check{Name: "mysql", Func: health.PingCheck(db)})
+	checks.Register(health.Check{Name: "migrations", Func: migrator.Check, Optional: !cfg.Database.RequireMigrations})
+	checks.Register(health.Check{Name: "disk", Func: health.DiskSpaceCheck(cfg.Log.Dir, cfg.Health.DiskMinFreeMB<<20), Optional: true})
+
+	// 4. Đăng ký Custom Validator
+	customValidator.RegisterCustomValidations()
+	logger.DebugLogger.Println("Đã đăng ký custom validators.")
+
+	// 5. Khởi tạo các tầng: Repo → Controller → Handler
+	userRepo := user.NewUserRepo(db, cfg.Database.Timeouts)
+	auditRepo := audit.NewRepo(db, cfg.Database.Timeouts.Default)
+	userCtrl := user.NewUserController(userRepo, database.NewTxManager(db), auditRepo)
+	userHandler := user.NewUserHandler(userCtrl)
+	auditHandler := audit.NewHandler(auditRepo)
+	logger.TraceLogger.Println("Đã khởi tạo các dependency.")
+
+	// 6. Khởi tạo Router và chuỗi middleware global (thứ tự = thứ tự bọc, ngoài cùng trước)
+	trusted, err := server.ParseTrustedProxies(cfg.Server.TrustedProxies)
+	if err != nil {
+		logger.ErrorLogger.Println("Cấu hình trusted proxies không hợp lệ:", err)
+		abort()
+		return 1
+	}
+	apiKeys, err := server.ParseAPIKeys(cfg.Server.Auth.Keys)
+	if err != nil {
+		logger.ErrorLogger.Println("Cấu hình API key không hợp lệ:", err)
+		abort()
+		return 1
+	}
+	auth := server.APIKeyAuth(cfg.Server.Auth.Header, apiKeys, cfg.Server.Auth.Required)
+	router := server.NewRouter(cfg.Server.API, auth, userHandler, auditHandler)
+	router.Use(server.RequestID(), server.RealIP(trusted))
+	if cfg.Tracing.Enabled {
+		tcfg := cfg.Tracing
+		if !filepath.IsAbs(tcfg.File) {
+			tcfg.File = filepath.Join(cfg.Log.Dir, tcfg.File)
+		}
+		exporter, err := tracing.NewExporter(tcfg)
+		if err != nil {
+			logger.ErrorLogger.Println("Không thể khởi tạo tracing:", err)
+			abort()
+			return 1
+		}
+		tracer := tracing.New(tcfg, exporter)
+		app.Append(lifecycle.Hook{Name: "tracing", OnStop: tracer.Shutdown})
+		router.Use(server.Tracing(tracer))
+	}
+	if cfg.Log.Access.Enabled {
+		router.Use(server.AccessLog(logger.OpenAccessLog(cfg.Log), cfg.Log.Access.Format, trusted))
+	}
+	if cfg.Metrics.Enabled {
+		router.Use(server.Metrics())
+		router.Handle("GET "+cfg.Metrics.Path, metrics.Handler())
+	}
+	router.Use(
+		server.Recover(cfg.Server.CrashOnPanic),
+		server.CORS(cfg.Server.CORS),
+		server.Compress(cfg.Server.Compression),
+	)
+	if cfg.Server.RateLimit.Enabled {
+		apiKey := server.KeyByAPIKey(cfg.Server.Auth.Header, apiKeys)
+		// Probe và /metrics không dùng chung quota với API
+		exempt := []string{"GET /livez", "GET /readyz", "GET /healthz"}
+		if cfg.Metrics.Enabled {
+			exempt = append(exempt, "GET "+cfg.Metrics.Path)
+		}
+		limiter := server.NewRateLimiter(cfg.Server.RateLimit, apiKey, ratelimit.NewMemoryStore(), router.Route, exempt...)
+		router.Use(limiter.Middleware())
+	}
+	router.Use(
+		server.Timeout(cfg.Server.RequestTimeout),
+		server.BodyLimit(cfg.Server.MaxBodyBytes),
+	)
+	router.Handle("GET /livez", checks.Livez())
+	router.Handle("GET /readyz", checks.Readyz())
+	router.Handle("GET /healthz", checks.Healthz())
+	logger.DebugLogger.Println("Đã khởi tạo router.")
+
+	// Server quản trị ở cổng riêng, khởi động trước để theo dõi được cả server chính
+	if cfg.Admin.Enabled {
+		adminSrv, err := admin.NewServer(cfg.Admin, admin.NewHandler(admin.Options{Config: *cfg, DB: db, Health: checks.Details()}))
+		if err != nil {
+			logger.ErrorLogger.Println("Cấu hình server quản trị không hợp lệ:", err)
+			abort()
+			return 1
+		}
+		app.Append(server.Hook("admin server", adminSrv, app))
+		logger.InfoLogger.Printf("Server quản trị đang chạy tại %s", adminSrv.Addr)
+	}
+
+	// 7. Khởi động Server, chờ tín hiệu tắt rồi dừng các thành phần theo thứ tự ngược
+	srv := server.NewHTTPServer(cfg.Server, router)
+	app.Append(server.Hook("http server", srv, app))
+	// Dừng trước http server (thứ tự ngược) để /readyz báo 503 ngay khi bắt đầu
+	// tắt, rồi chờ DrainDelay cho load balancer ngừng gửi request mới
+	app.Append(lifecycle.Hook{
+		Name: "health",
+		OnStop: func(ctx context.Context) error {
+			checks.Drain()
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(cfg.Health.DrainDelay):
+				return nil
+			}
+		},
+	})
+	logger.InfoLogger.Printf("Server đang chạy tại %s", srv.Addr)
+
+	if err := app.Run(context.Background()); err != nil {
+		logger.ErrorLogger.Println("Lỗi khi chạy server:", err)
+		return 1
+	}
+	return 0
+}
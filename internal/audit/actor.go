@@ -0,0 +1,23 @@
+package audit
+
+import (
+	"context"
+)
+
+// Anonymous là actor khi request không gắn danh tính nào
+const Anonymous = "anonymous"
+
+type actorKey struct{}
+
+// WithActor gắn danh tính người thực hiện vào ctx (middleware xác thực gọi hàm này)
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext trả về actor trong ctx, mặc định là Anonymous
+func ActorFromContext(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey{}).(string); ok && actor != "" {
+		return actor
+	}
+	return Anonymous
+}
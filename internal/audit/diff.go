@@ -0,0 +1,108 @@
+package audit
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Diff so sánh hai struct cùng kiểu (hoặc con trỏ tới struct) và trả về các
+// trường khác nhau. before = nil khi tạo mới, after = nil khi xóa; khi đó mọi
+// trường đều được coi là thay đổi. Tên trường lấy theo tag json nếu có.
+//
+// Audit log cần giá trị thật để truy vết nên Diff không che trường
+// `log:"mask"`. Trường `log:"redact"` (mật khẩu, token) chỉ được ghi là đã
+// đổi, giá trị thay bằng [REDACTED].
+func Diff(before, after any) map[string]Change {
+	changes := map[string]Change{}
+
+	bv, av := structValue(before), structValue(after)
+	var t reflect.Type
+	switch {
+	case bv.IsValid():
+		t = bv.Type()
+	case av.IsValid():
+		t = av.Type()
+	default:
+		return changes
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name := fieldName(f)
+		if name == "" {
+			continue
+		}
+
+		var c Change
+		switch {
+		case !bv.IsValid():
+			c.New = av.Field(i).Interface()
+		case !av.IsValid():
+			c.Old = bv.Field(i).Interface()
+		default:
+			o, n := bv.Field(i).Interface(), av.Field(i).Interface()
+			if equal(o, n) {
+				continue
+			}
+			c.Old, c.New = o, n
+		}
+		if f.Tag.Get("log") == "redact" {
+			c = redactChange(c)
+		}
+		changes[name] = c
+	}
+	return changes
+}
+
+// redactedValue thay cho giá trị của trường `log:"redact"`
+const redactedValue = "[REDACTED]"
+
+func redactChange(c Change) Change {
+	if c.Old != nil {
+		c.Old = redactedValue
+	}
+	if c.New != nil {
+		c.New = redactedValue
+	}
+	return c
+}
+
+// structValue bỏ con trỏ; trả về Value rỗng nếu v là nil hoặc không phải struct
+func structValue(v any) reflect.Value {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return reflect.Value{}
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return reflect.Value{}
+	}
+	return rv
+}
+
+func fieldName(f reflect.StructField) string {
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return ""
+	}
+	if name, _, _ := strings.Cut(tag, ","); name != "" {
+		return name
+	}
+	return f.Name
+}
+
+// equal so time.Time bằng Equal vì hai mốc giống nhau có thể khác location
+func equal(a, b any) bool {
+	if ta, ok := a.(time.Time); ok {
+		if tb, ok := b.(time.Time); ok {
+			return ta.Equal(tb)
+		}
+	}
+	return reflect.DeepEqual(a, b)
+}
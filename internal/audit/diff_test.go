@@ -0,0 +1,59 @@
+package audit
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type account struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email" log:"mask"`
+	Password  string    `json:"password" log:"redact"`
+	Internal  string    `json:"-"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Note      string
+	private   string
+}
+
+func TestDiff(t *testing.T) {
+	at := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	old := account{ID: 1, Name: "an", Email: "an@example.com", Password: "cu", Internal: "x", UpdatedAt: at, private: "a"}
+
+	tests := []struct {
+		name          string
+		before, after any
+		want          map[string]Change
+	}{
+		{"không đổi gì", old, old, map[string]Change{}},
+		{"đổi trường thường, email giữ giá trị thật",
+			old, account{ID: 1, Name: "binh", Email: "binh@example.com", Password: "cu", Internal: "x", UpdatedAt: at},
+			map[string]Change{
+				"name":  {Old: "an", New: "binh"},
+				"email": {Old: "an@example.com", New: "binh@example.com"},
+			}},
+		{"trường redact chỉ ghi là đã đổi",
+			&old, &account{ID: 1, Name: "an", Email: "an@example.com", Password: "moi", UpdatedAt: at},
+			map[string]Change{"password": {Old: redactedValue, New: redactedValue}}},
+		{"cùng thời điểm khác location là không đổi, json:\"-\" và trường private bị bỏ qua",
+			old, account{ID: 1, Name: "an", Email: "an@example.com", Password: "cu", Internal: "y", UpdatedAt: at.In(time.FixedZone("ICT", 7*3600)), private: "b"},
+			map[string]Change{}},
+		{"tạo mới", nil, &old, map[string]Change{
+			"id": {New: 1}, "name": {New: "an"}, "email": {New: "an@example.com"},
+			"password": {New: redactedValue}, "updated_at": {New: at}, "Note": {New: ""},
+		}},
+		{"xóa", &old, (*account)(nil), map[string]Change{
+			"id": {Old: 1}, "name": {Old: "an"}, "email": {Old: "an@example.com"},
+			"password": {Old: redactedValue}, "updated_at": {Old: at}, "Note": {Old: ""},
+		}},
+		{"cả hai nil", nil, nil, map[string]Change{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Diff(tt.before, tt.after); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff = %#v\nmuốn   %#v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package audit
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"vadilatorgolang/package/bind"
+	"vadilatorgolang/package/logger"
+	"vadilatorgolang/package/respond"
+)
+
+type Handler struct {
+	Repo Repository
+}
+
+func NewHandler(repo Repository) *Handler {
+	return &Handler{Repo: repo}
+}
+
+// ListParams là query của GET /audit; from/to theo RFC 3339, khoảng [from, to)
+type ListParams struct {
+	Actor      string    `query:"actor"`
+	Action     Action    `query:"action" validate:"omitempty,oneof=create update delete"`
+	TargetType string    `query:"target_type"`
+	TargetID   int       `query:"target_id" validate:"gte=0"`
+	From       time.Time `query:"from"`
+	To         time.Time `query:"to"`
+	Limit      int       `query:"limit" default:"50" validate:"gte=1,lte=200"`
+	Offset     int       `query:"offset" validate:"gte=0"`
+}
+
+// TargetParams là tham số của GET /<target>/{id}/audit
+type TargetParams struct {
+	ID     int `path:"id" validate:"gt=0"`
+	Limit  int `query:"limit" default:"50" validate:"gte=1,lte=200"`
+	Offset int `query:"offset" validate:"gte=0"`
+}
+
+// ListHandler: GET /audit?actor=&action=&target_type=&target_id=&from=&to=&limit=&offset=
+func (h *Handler) ListHandler(w http.ResponseWriter, r *http.Request) {
+	p, err := bind.Bind[ListParams](r)
+	if err != nil {
+		respond.Error(w, r, err)
+		return
+	}
+	h.list(w, r, Filter{
+		Actor:      p.Actor,
+		Action:     p.Action,
+		TargetType: p.TargetType,
+		TargetID:   p.TargetID,
+		From:       p.From.UTC(),
+		To:         p.To.UTC(),
+		Limit:      p.Limit,
+		Offset:     p.Offset,
+	})
+}
+
+// TargetHandler trả về handler liệt kê audit log của một đối tượng, lấy ID
+// từ {id} trên đường dẫn, ví dụ GET /user/{id}/audit.
+func (h *Handler) TargetHandler(targetType string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		p, err := bind.Bind[TargetParams](r)
+		if err != nil {
+			respond.Error(w, r, err)
+			return
+		}
+		h.list(w, r, Filter{TargetType: targetType, TargetID: p.ID, Limit: p.Limit, Offset: p.Offset})
+	}
+}
+
+func (h *Handler) list(w http.ResponseWriter, r *http.Request, f Filter) {
+	entries, total, err := h.Repo.List(r.Context(), f)
+	if err != nil {
+		respond.Error(w, r, fmt.Errorf("audit List: %w", err))
+		return
+	}
+
+	logger.FromRequest(r).Info("Lấy audit log thành công", "count", len(entries), "total", total)
+	respond.JSON(w, http.StatusOK, ListResponse{
+		Message: "Lấy audit log thành công",
+		Data:    entries,
+		Page:    Page{Limit: f.Limit, Offset: f.Offset, Total: total},
+	})
+}
@@ -0,0 +1,66 @@
+package audit
+
+import (
+	"time"
+)
+
+// Action là loại thay đổi được ghi vào audit log
+type Action string
+
+const (
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+)
+
+// Valid báo a có phải một Action đã biết
+func (a Action) Valid() bool {
+	switch a {
+	case ActionCreate, ActionUpdate, ActionDelete:
+		return true
+	}
+	return false
+}
+
+// Change là giá trị trước/sau của một trường. Old là null khi tạo mới, New là null khi xóa.
+type Change struct {
+	Old any `json:"old"`
+	New any `json:"new"`
+}
+
+// Entry là một dòng trong audit log
+type Entry struct {
+	ID         int64             `json:"id"`
+	Actor      string            `json:"actor"`
+	Action     Action            `json:"action"`
+	TargetType string            `json:"target_type"`
+	TargetID   int               `json:"target_id"`
+	RequestID  string            `json:"request_id,omitempty"`
+	Changes    map[string]Change `json:"changes"`
+	CreatedAt  time.Time         `json:"created_at"`
+}
+
+// Filter lọc audit log; trường rỗng/0 nghĩa là không lọc theo trường đó
+type Filter struct {
+	Actor      string
+	Action     Action
+	TargetType string
+	TargetID   int
+	From       time.Time
+	To         time.Time
+	Limit      int
+	Offset     int
+}
+
+// Page là thông tin phân trang trả về cùng danh sách
+type Page struct {
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+	Total  int `json:"total"`
+}
+
+type ListResponse struct {
+	Message string  `json:"msg"`
+	Data    []Entry `json:"data"`
+	Page    Page    `json:"page"`
+}
@@ -0,0 +1,153 @@
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"vadilatorgolang/package/database"
+	"vadilatorgolang/package/requestid"
+)
+
+// Recorder ghi một thay đổi vào audit log. Gọi bên trong WithinTx để dòng
+// audit được commit/rollback cùng thay đổi dữ liệu.
+type Recorder interface {
+	Record(ctx context.Context, e *Entry) error
+}
+
+// Repository đọc/ghi bảng audit_log
+type Repository interface {
+	Recorder
+	List(ctx context.Context, f Filter) ([]Entry, int, error)
+}
+
+// Repo là Repository dựa trên MySQL
+type Repo struct {
+	DB      *sql.DB
+	Timeout time.Duration
+}
+
+// NewRepo tạo một audit repository
+func NewRepo(db *sql.DB, timeout time.Duration) *Repo {
+	return &Repo{DB: db, Timeout: timeout}
+}
+
+func (r *Repo) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if r.Timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, r.Timeout)
+}
+
+// Record điền Actor, RequestID, CreatedAt từ ctx nếu còn trống rồi insert e
+func (r *Repo) Record(ctx context.Context, e *Entry) error {
+	if e.Actor == "" {
+		e.Actor = ActorFromContext(ctx)
+	}
+	if e.RequestID == "" {
+		e.RequestID = requestid.FromContext(ctx)
+	}
+	if e.CreatedAt.IsZero() {
+		e.CreatedAt = time.Now().UTC()
+	}
+	if e.Changes == nil {
+		e.Changes = map[string]Change{}
+	}
+	changes, err := json.Marshal(e.Changes)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	res, err := database.Conn(ctx, r.DB).ExecContext(ctx,
+		"insert into audit_log(actor,action,target_type,target_id,request_id,changes,created_at) values(?,?,?,?,?,?,?)",
+		e.Actor, string(e.Action), e.TargetType, e.TargetID, e.RequestID, changes, e.CreatedAt)
+	if err != nil {
+		return err
+	}
+	e.ID, err = res.LastInsertId()
+	return err
+}
+
+// List trả về một trang audit log mới nhất trước, cùng tổng số dòng khớp Filter
+func (r *Repo) List(ctx context.Context, f Filter) ([]Entry, int, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	where, args := f.where()
+	conn := database.Conn(ctx, r.DB)
+
+	var total int
+	if err := conn.QueryRowContext(ctx, "select count(*) from audit_log"+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := conn.QueryContext(ctx,
+		"select id,actor,action,target_type,target_id,request_id,changes,created_at from audit_log"+where+" order by created_at desc, id desc limit ? offset ?",
+		append(args, f.Limit, f.Offset)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	entries := []Entry{}
+	for rows.Next() {
+		var (
+			e       Entry
+			action  string
+			changes []byte
+		)
+		if err := rows.Scan(&e.ID, &e.Actor, &action, &e.TargetType, &e.TargetID, &e.RequestID, &changes, &e.CreatedAt); err != nil {
+			return nil, 0, err
+		}
+		e.Action = Action(action)
+		if err := json.Unmarshal(changes, &e.Changes); err != nil {
+			return nil, 0, err
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
+
+// where dựng mệnh đề WHERE từ các trường khác rỗng của f
+func (f Filter) where() (string, []any) {
+	var (
+		conds []string
+		args  []any
+	)
+	if f.Actor != "" {
+		conds = append(conds, "actor=?")
+		args = append(args, f.Actor)
+	}
+	if f.Action != "" {
+		conds = append(conds, "action=?")
+		args = append(args, string(f.Action))
+	}
+	if f.TargetType != "" {
+		conds = append(conds, "target_type=?")
+		args = append(args, f.TargetType)
+	}
+	if f.TargetID != 0 {
+		conds = append(conds, "target_id=?")
+		args = append(args, f.TargetID)
+	}
+	if !f.From.IsZero() {
+		conds = append(conds, "created_at>=?")
+		args = append(args, f.From)
+	}
+	if !f.To.IsZero() {
+		conds = append(conds, "created_at<?")
+		args = append(args, f.To)
+	}
+	if len(conds) == 0 {
+		return "", nil
+	}
+	return " where " + strings.Join(conds, " and "), args
+}
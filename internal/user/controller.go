@@ -1,63 +1,117 @@
 package user
 
 import (
-	"database/sql"
-	"errors"
+	"context"
+	"time"
+
+	"vadilatorgolang/internal/audit"
+	"vadilatorgolang/package/database"
+	"vadilatorgolang/package/tracing"
 )
 
+// AuditTarget là target_type của user trong audit log
+const AuditTarget = "user"
+
 // Controller giữ Repo (như file gốc của bạn)
 type UserController struct {
-	Repo UserRepository
+	Repo  UserRepository
+	Tx    database.Transactor
+	Audit audit.Recorder
 }
 
-// NewUserController nhận vào Repo (như file gốc của bạn)
-func NewUserController(r UserRepository) *UserController {
-	return &UserController{Repo: r}
+// NewUserController nhận vào Repo, Tx để gom thay đổi và audit vào một
+// transaction, và Audit để ghi lại mỗi thay đổi
+func NewUserController(r UserRepository, tx database.Transactor, rec audit.Recorder) *UserController {
+	return &UserController{Repo: r, Tx: tx, Audit: rec}
 }
 
 // --- LOGIC NGHIỆP VỤ ĐƯỢC ĐẶT TRỰC TIẾP TẠI ĐÂY ---
 
 // Create
-func (u *UserController) CreateUser(user *User) error {
-	// --- KIỂM TRA USERNAME ---
-	existingUser, err := u.Repo.GetUserByUsername(user.UserName)
-	if err != nil && err != sql.ErrNoRows {
-		return err // Lỗi database
-	}
-	if existingUser != nil {
-		return errors.New("username đã tồn tại") // Lỗi nghiệp vụ
-	}
+// Việc trùng username/email do index unique trong database quyết định,
+// repo trả về ErrUsernameTaken / ErrEmailTaken. Kiểm tra trước bằng SELECT
+// không an toàn khi có hai request đồng thời.
+func (u *UserController) CreateUser(ctx context.Context, user *User) (err error) {
+	ctx, span := tracing.Start(ctx, "UserController.CreateUser")
+	defer span.Finish(&err)
 
-	// --- KIỂM TRA EMAIL ---
-	existingUser, err = u.Repo.GetUserByEmail(user.Email)
-	if err != nil && err != sql.ErrNoRows {
-		return err // Lỗi database
+	if user.UpdatedAt.IsZero() {
+		user.UpdatedAt = user.CreatedAt
 	}
-	if existingUser != nil {
-		return errors.New("email đã tồn tại") // Lỗi nghiệp vụ
-	}
-
-	// Nếu mọi thứ ổn, gọi Repo
-	return u.Repo.CreateUser(user)
+	return u.Tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := u.Repo.CreateUser(ctx, user); err != nil {
+			return err
+		}
+		return u.record(ctx, audit.ActionCreate, user.ID, nil, user)
+	})
 }
 
 // GetAllContact
-func (u *UserController) GetAllContact() ([]User, error) {
-	return u.Repo.GetAllUser()
+func (u *UserController) GetAllContact(ctx context.Context) (users []User, err error) {
+	ctx, span := tracing.Start(ctx, "UserController.GetAllContact")
+	defer span.Finish(&err)
+
+	return u.Repo.GetAllUser(ctx)
 }
 
 // GetByID
-func (u *UserController) GetUserByID(id int) (*User, error) {
-	return u.Repo.GetUserByID(id)
+func (u *UserController) GetUserByID(ctx context.Context, id int) (user *User, err error) {
+	ctx, span := tracing.Start(ctx, "UserController.GetUserByID", tracing.Int("user.id", id))
+	defer span.Finish(&err)
+
+	return u.Repo.GetUserByID(ctx, id)
 }
 
-// Update
-func (u *UserController) UpdateUserByID(user *User) error {
-	
-	return u.Repo.UpdateUserByID(user)
+// PatchUserByID áp patch lên bản ghi hiện tại của user id rồi lưu lại, trả
+// về bản ghi sau cập nhật. Bản ghi cũ được đọc trong cùng transaction để
+// audit có giá trị trước/sau; updated_at luôn là thời điểm cập nhật.
+func (u *UserController) PatchUserByID(ctx context.Context, id int, patch func(*User)) (user *User, err error) {
+	ctx, span := tracing.Start(ctx, "UserController.PatchUserByID", tracing.Int("user.id", id))
+	defer span.Finish(&err)
+
+	err = u.Tx.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := u.Repo.GetUserByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		after := *before
+		patch(&after)
+		after.ID, after.UpdatedAt = id, time.Now()
+		if err := u.Repo.UpdateUserByID(ctx, &after); err != nil {
+			return err
+		}
+		user = &after
+		return u.record(ctx, audit.ActionUpdate, id, before, user)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
 }
 
 // DeleteByID
-func (u *UserController) DeleteByID(id int) error {
-	return u.Repo.DeleteUserByID(id)
+func (u *UserController) DeleteByID(ctx context.Context, id int) (err error) {
+	ctx, span := tracing.Start(ctx, "UserController.DeleteByID", tracing.Int("user.id", id))
+	defer span.Finish(&err)
+
+	return u.Tx.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := u.Repo.GetUserByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := u.Repo.DeleteUserByID(ctx, id); err != nil {
+			return err
+		}
+		return u.record(ctx, audit.ActionDelete, id, before, nil)
+	})
+}
+
+// record ghi một dòng audit cho user id; before/after là *User hoặc nil
+func (u *UserController) record(ctx context.Context, action audit.Action, id int, before, after *User) error {
+	return u.Audit.Record(ctx, &audit.Entry{
+		Action:     action,
+		TargetType: AuditTarget,
+		TargetID:   id,
+		Changes:    audit.Diff(before, after),
+	})
 }
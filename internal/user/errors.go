@@ -0,0 +1,45 @@
+package user
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+
+	"vadilatorgolang/package/apperr"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// Lỗi nghiệp vụ của domain user
+var (
+	ErrUserNotFound  = apperr.NotFound("user_not_found", "Không tìm thấy user")
+	ErrUsernameTaken = apperr.Conflict("username_taken", "username đã tồn tại")
+	ErrEmailTaken    = apperr.Conflict("email_taken", "email đã tồn tại")
+)
+
+// errDuplicateEntry là mã lỗi MySQL ER_DUP_ENTRY
+const errDuplicateEntry = 1062
+
+// Tên các index unique trong migration 0002
+const (
+	uniqueUsernameKey = "uq_nguoi_dung_username"
+	uniqueEmailKey    = "uq_nguoi_dung_email"
+)
+
+// translateError chuyển lỗi của database thành lỗi nghiệp vụ
+func translateError(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrUserNotFound.Wrap(err)
+	}
+	var myErr *mysql.MySQLError
+	if !errors.As(err, &myErr) || myErr.Number != errDuplicateEntry {
+		return err
+	}
+	switch {
+	case strings.Contains(myErr.Message, uniqueUsernameKey):
+		return ErrUsernameTaken.Wrap(err)
+	case strings.Contains(myErr.Message, uniqueEmailKey):
+		return ErrEmailTaken.Wrap(err)
+	}
+	return err
+}
@@ -0,0 +1,113 @@
+package user
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"vadilatorgolang/internal/audit"
+	"vadilatorgolang/package/apperr"
+	customValidator "vadilatorgolang/package/validator"
+)
+
+func TestMain(m *testing.M) {
+	customValidator.RegisterCustomValidations()
+	os.Exit(m.Run())
+}
+
+// fakeRepo giữ user trong bộ nhớ, chỉ cần cho GetUserByID/UpdateUserByID
+type fakeRepo struct {
+	UserRepository
+	users   map[int]User
+	updates int
+}
+
+func (f *fakeRepo) GetUserByID(ctx context.Context, id int) (*User, error) {
+	u, ok := f.users[id]
+	if !ok {
+		return nil, ErrUserNotFound
+	}
+	return &u, nil
+}
+
+func (f *fakeRepo) UpdateUserByID(ctx context.Context, u *User) error {
+	f.updates++
+	f.users[u.ID] = *u
+	return nil
+}
+
+type fakeTx struct{}
+
+func (fakeTx) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type fakeRecorder struct{}
+
+func (fakeRecorder) Record(ctx context.Context, e *audit.Entry) error { return nil }
+
+func TestUpdateUserHandler(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	stored := User{ID: 5, UserName: "khanhchauu", Email: "khanh@example.com", Age: 20, CreatedAt: created}
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+		field  string // trường phải có trong lỗi validate
+		want   User   // bản ghi sau request
+	}{
+		{"email sai định dạng", `{"email":"khong-phai-email"}`, http.StatusBadRequest, "email", stored},
+		{"chưa đủ tuổi", `{"Age":17}`, http.StatusBadRequest, "age", stored},
+		{"username quá ngắn", `{"UserName":"ab"}`, http.StatusBadRequest, "username", stored},
+		{"username có ký tự lạ", `{"username":"khanh chau"}`, http.StatusBadRequest, "username", stored},
+		{"không cho ghi created_at", `{"CreatedAt":"2000-01-01T00:00:00Z"}`, http.StatusBadRequest, "", stored},
+		{"chỉ đổi trường có trong body", `{"Email":"moi@example.com"}`, http.StatusOK, "",
+			User{ID: 5, UserName: "khanhchauu", Email: "moi@example.com", Age: 20, CreatedAt: created}},
+		{"đổi nhiều trường", `{"username":"khanh_moi","age":30}`, http.StatusOK, "",
+			User{ID: 5, UserName: "khanh_moi", Email: "khanh@example.com", Age: 30, CreatedAt: created}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeRepo{users: map[int]User{5: stored}}
+			h := NewUserHandler(NewUserController(repo, fakeTx{}, fakeRecorder{}))
+
+			r := httptest.NewRequest(http.MethodPut, "/user/5", strings.NewReader(tt.body))
+			r.Header.Set("Content-Type", "application/json")
+			r.SetPathValue("id", "5")
+			w := httptest.NewRecorder()
+			h.UpdateUserHandler(w, r)
+
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, muốn %d, body %s", w.Code, tt.status, w.Body)
+			}
+			got := repo.users[5]
+			got.UpdatedAt = time.Time{}
+			if got != tt.want {
+				t.Errorf("bản ghi = %+v, muốn %+v", got, tt.want)
+			}
+			if tt.status != http.StatusOK && repo.updates != 0 {
+				t.Errorf("request lỗi vẫn ghi vào database %d lần", repo.updates)
+			}
+			if tt.field == "" {
+				return
+			}
+			var p apperr.Problem
+			if err := json.Unmarshal(w.Body.Bytes(), &p); err != nil {
+				t.Fatal(err)
+			}
+			found := false
+			for _, fe := range p.Errors {
+				found = found || fe.Field == tt.field
+			}
+			if !found {
+				t.Errorf("lỗi = %+v, muốn có trường %q", p.Errors, tt.field)
+			}
+		})
+	}
+}
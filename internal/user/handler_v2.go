@@ -0,0 +1,108 @@
+package user
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"vadilatorgolang/package/bind"
+	"vadilatorgolang/package/logger"
+	"vadilatorgolang/package/respond"
+)
+
+// Handler của API v2: body và response theo schema trong docs.yaml, trả về
+// thẳng UserV2 (hoặc mảng UserV2) thay vì bọc trong UserResponse.
+
+// CreateUserV2Handler
+func (u *UserHandler) CreateUserV2Handler(w http.ResponseWriter, r *http.Request) {
+	req, err := bind.Bind[CreateUserRequestV2](r)
+	if err != nil {
+		respond.Error(w, r, err)
+		return
+	}
+
+	newUser := &User{
+		UserName:  req.UserName,
+		Email:     req.Email,
+		Age:       req.Age,
+		CreatedAt: time.Now(),
+	}
+	if err := u.Ctrl.CreateUser(r.Context(), newUser); err != nil {
+		respond.Error(w, r, fmt.Errorf("CreateUser: %w", err))
+		return
+	}
+
+	r = logger.WithUserID(r, newUser.ID)
+	logger.FromRequest(r).Info("Tạo user thành công", "user", newUser)
+	w.Header().Set("Location", fmt.Sprintf("%s/%d", r.URL.Path, newUser.ID))
+	respond.JSON(w, http.StatusCreated, toV2(newUser))
+}
+
+// GetUserByIDV2Handler
+func (u *UserHandler) GetUserByIDV2Handler(w http.ResponseWriter, r *http.Request) {
+	p, err := bind.Bind[UserIDParams](r)
+	if err != nil {
+		respond.Error(w, r, err)
+		return
+	}
+	r = logger.WithUserID(r, p.ID)
+	user, err := u.Ctrl.GetUserByID(r.Context(), p.ID)
+	if err != nil {
+		respond.Error(w, r, fmt.Errorf("GetUserByID %d: %w", p.ID, err))
+		return
+	}
+
+	logger.FromRequest(r).Info("Lấy user thành công")
+	respond.JSON(w, http.StatusOK, toV2(user))
+}
+
+// GetAllUserV2Handler
+func (u *UserHandler) GetAllUserV2Handler(w http.ResponseWriter, r *http.Request) {
+	users, err := u.Ctrl.GetAllContact(r.Context())
+	if err != nil {
+		respond.Error(w, r, fmt.Errorf("GetAllContact: %w", err))
+		return
+	}
+
+	logger.FromRequest(r).Info("Lấy tất cả user thành công", "count", len(users))
+	respond.JSON(w, http.StatusOK, toV2List(users))
+}
+
+// UpdateUserV2Handler chỉ đổi các trường có trong body
+func (u *UserHandler) UpdateUserV2Handler(w http.ResponseWriter, r *http.Request) {
+	in, err := bind.Bind[UpdateUserInputV2](r)
+	if err != nil {
+		respond.Error(w, r, err)
+		return
+	}
+	r = logger.WithUserID(r, in.ID)
+
+	user, err := u.Ctrl.PatchUserByID(r.Context(), in.ID, func(user *User) {
+		patchUser(user, in.UserName, in.Email, in.Age)
+	})
+	if err != nil {
+		respond.Error(w, r, fmt.Errorf("PatchUserByID %d: %w", in.ID, err))
+		return
+	}
+
+	logger.FromRequest(r).Info("Cập nhật user thành công")
+	respond.JSON(w, http.StatusOK, toV2(user))
+}
+
+// DeleteUserV2Handler trả 204 không có body
+func (u *UserHandler) DeleteUserV2Handler(w http.ResponseWriter, r *http.Request) {
+	p, err := bind.Bind[UserIDParams](r)
+	if err != nil {
+		respond.Error(w, r, err)
+		return
+	}
+	r = logger.WithUserID(r, p.ID)
+
+	if err := u.Ctrl.DeleteByID(r.Context(), p.ID); err != nil {
+		respond.Error(w, r, fmt.Errorf("DeleteByID %d: %w", p.ID, err))
+		return
+	}
+
+	logger.FromRequest(r).Info("Xóa user thành công")
+	w.WriteHeader(http.StatusNoContent)
+}
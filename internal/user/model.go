@@ -4,26 +4,57 @@ import (
 	"time"
 )
 
+// Tag `log` điều khiển việc che dữ liệu khi ghi log (xem package/logger).
+// User là bản ghi nội bộ; dạng trả về client do từng phiên bản API quyết
+// định (xem version.go).
 type User struct {
 	ID        int
 	UserName  string
-	Email     string
+	Email     string `log:"mask"`
 	Age       int
 	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// UserIDParams là {id} trên đường dẫn của các route /user/{id}
+type UserIDParams struct {
+	ID int `path:"id" validate:"gt=0"`
 }
 
 type CreateUserRequest struct {
 	UserName string `json:"user_name" validate:"required,min=3,max=50,username_chars"`
-	Email    string `json:"email" validate:"required,email"`
+	Email    string `json:"email" validate:"required,email" log:"mask"`
 	Age      int    `json:"age" validate:"omitempty,gte=18"`
 }
 
+// UpdateUserRequest là dữ liệu của PUT /user/{id} (v1). Tên trường trong body
+// không phân biệt hoa thường, nên client cũ gửi "UserName", "Email", "Age"
+// vẫn khớp. Trường bỏ trống giữ nguyên giá trị cũ.
 type UpdateUserRequest struct {
-	Email string `json:"email" validate:"omitempty,email"`
-	Age   int    `json:"age" validate:"omitempty,gte=18"`
+	ID       int    `path:"id" json:"-" validate:"gt=0"`
+	UserName string `json:"username" validate:"omitempty,min=3,max=50,username_chars"`
+	Email    string `json:"email" validate:"omitempty,email" log:"mask"`
+	Age      int    `json:"age" validate:"omitempty,gte=18"`
 }
 
+// UserResponse là body response của API v1
 type UserResponse struct {
-	Message string `json:"msg"`
-	Data    []User `json:"data"`
+	Message string   `json:"msg"`
+	Data    []UserV1 `json:"data"`
+}
+
+// CreateUserRequestV2 là body của POST /api/v2/users
+type CreateUserRequestV2 struct {
+	UserName string `json:"username" validate:"required,min=3,max=50,username_chars"`
+	Email    string `json:"email" validate:"required,email" log:"mask"`
+	Age      int    `json:"age" validate:"omitempty,gte=18"`
+}
+
+// UpdateUserInputV2 là dữ liệu của PUT /api/v2/users/{id}. Trường bỏ trống
+// giữ nguyên giá trị cũ.
+type UpdateUserInputV2 struct {
+	ID       int    `path:"id" json:"-" validate:"gt=0"`
+	UserName string `json:"username" validate:"omitempty,min=3,max=50,username_chars"`
+	Email    string `json:"email" validate:"omitempty,email" log:"mask"`
+	Age      int    `json:"age" validate:"omitempty,gte=18"`
 }
@@ -1,58 +1,88 @@
 package user
 
 import (
+	"context"
 	"database/sql"
-	"fmt"
+	"time"
+
+	"vadilatorgolang/package/config"
+	"vadilatorgolang/package/database"
 )
 
 // UserRepository là interface định nghĩa các phương thức cho database
 type UserRepository interface {
-	CreateUser(c *User) error
-	GetUserByID(id int) (*User, error)
-	GetAllUser() ([]User, error)
-	UpdateUserByID(u *User) error
-	DeleteUserByID(id int) error
-	GetUserByEmail(email string) (*User, error)
-	GetUserByUsername(username string) (*User, error)
+	CreateUser(ctx context.Context, c *User) error
+	GetUserByID(ctx context.Context, id int) (*User, error)
+	GetAllUser(ctx context.Context) ([]User, error)
+	UpdateUserByID(ctx context.Context, u *User) error
+	DeleteUserByID(ctx context.Context, id int) error
+	GetUserByEmail(ctx context.Context, email string) (*User, error)
+	GetUserByUsername(ctx context.Context, username string) (*User, error)
 }
 
 // UserRepo là struct triển khai UserRepository
 type UserRepo struct {
-	DB *sql.DB
+	DB       *sql.DB
+	Timeouts config.TimeoutConfig
 }
 
 // NewUserRepo tạo một repository mới
-func NewUserRepo(db *sql.DB) UserRepository {
-	return &UserRepo{DB: db}
+func NewUserRepo(db *sql.DB, timeouts config.TimeoutConfig) UserRepository {
+	return &UserRepo{DB: db, Timeouts: timeouts}
+}
+
+// withTimeout gắn deadline của thao tác vào ctx (0 = dùng Timeouts.Default)
+func (r *UserRepo) withTimeout(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	if d <= 0 {
+		d = r.Timeouts.Default
+	}
+	if d <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, d)
 }
 
 // Create
-func (r *UserRepo) CreateUser(c *User) error {
-	_, err := r.DB.Exec("insert into nguoi_dung(username,email,age,created_at) values(?,?,?,?)", c.UserName, c.Email, c.Age, c.CreatedAt)
+func (r *UserRepo) CreateUser(ctx context.Context, c *User) error {
+	ctx, cancel := r.withTimeout(ctx, r.Timeouts.Create)
+	defer cancel()
+
+	res, err := database.Conn(ctx, r.DB).ExecContext(ctx, "insert into nguoi_dung(username,email,age,created_at,updated_at) values(?,?,?,?,?)", c.UserName, c.Email, c.Age, c.CreatedAt, c.UpdatedAt)
+	if err != nil {
+		return translateError(err)
+	}
+	id, err := res.LastInsertId()
 	if err != nil {
 		return err
 	}
+	c.ID = int(id)
 	return nil // Sửa: trả về nil khi thành công
 }
 
 // Get by ID
-func (r *UserRepo) GetUserByID(id int) (*User, error) {
-	row := r.DB.QueryRow("select id,username,email,age,created_at from nguoi_dung where id=?", id)
+func (r *UserRepo) GetUserByID(ctx context.Context, id int) (*User, error) {
+	ctx, cancel := r.withTimeout(ctx, r.Timeouts.Get)
+	defer cancel()
+
+	row := database.Conn(ctx, r.DB).QueryRowContext(ctx, "select id,username,email,age,created_at,updated_at from nguoi_dung where id=?", id)
 	var c User
-	if err := row.Scan(&c.ID, &c.UserName, &c.Email, &c.Age, &c.CreatedAt); err != nil {
-		return nil, err
+	if err := row.Scan(&c.ID, &c.UserName, &c.Email, &c.Age, &c.CreatedAt, &c.UpdatedAt); err != nil {
+		return nil, translateError(err)
 	}
 	return &c, nil
 }
 
 // === THÊM MỚI: Get by Email ===
 // GetUserByEmail tìm người dùng bằng email
-func (r *UserRepo) GetUserByEmail(email string) (*User, error) {
-	row := r.DB.QueryRow("select id,username,email,age,created_at from nguoi_dung where email=?", email)
+func (r *UserRepo) GetUserByEmail(ctx context.Context, email string) (*User, error) {
+	ctx, cancel := r.withTimeout(ctx, r.Timeouts.Get)
+	defer cancel()
+
+	row := database.Conn(ctx, r.DB).QueryRowContext(ctx, "select id,username,email,age,created_at,updated_at from nguoi_dung where email=?", email)
 	var c User
-	if err := row.Scan(&c.ID, &c.UserName, &c.Email, &c.Age, &c.CreatedAt); err != nil {
-		// err ở đây có thể là 'sql.ErrNoRows' (không tìm thấy)
-		return nil, err
+	if err := row.Scan(&c.ID, &c.UserName, &c.Email, &c.Age, &c.CreatedAt, &c.UpdatedAt); err != nil {
+		// sql.ErrNoRows (không tìm thấy) được chuyển thành ErrUserNotFound
+		return nil, translateError(err)
 	}
 	return &c, nil // Tìm thấy user
 }
@@ -60,8 +90,11 @@ func (r *UserRepo) GetUserByEmail(email string) (*User, error) {
 // === KẾT THÚC THÊM MỚI ===
 
 // Get all
-func (r *UserRepo) GetAllUser() ([]User, error) {
-	row, err := r.DB.Query("select id,username,email,age,created_at from nguoi_dung")
+func (r *UserRepo) GetAllUser(ctx context.Context) ([]User, error) {
+	ctx, cancel := r.withTimeout(ctx, r.Timeouts.List)
+	defer cancel()
+
+	row, err := database.Conn(ctx, r.DB).QueryContext(ctx, "select id,username,email,age,created_at,updated_at from nguoi_dung")
 	if err != nil {
 		return nil, err
 	}
@@ -70,44 +103,64 @@ func (r *UserRepo) GetAllUser() ([]User, error) {
 	var c []User
 	for row.Next() {
 		var p User
-		if err := row.Scan(&p.ID, &p.UserName, &p.Email, &p.Age, &p.CreatedAt); err != nil {
+		if err := row.Scan(&p.ID, &p.UserName, &p.Email, &p.Age, &p.CreatedAt, &p.UpdatedAt); err != nil {
 			return nil, err
 		}
 		c = append(c, p) // Thêm: Phải append vào slice
 	}
+	// Bị hủy giữa chừng (client ngắt, hết thời gian) thì Next trả false, lỗi nằm ở Err
+	if err := row.Err(); err != nil {
+		return nil, err
+	}
 	return c, nil
 }
 
 // Update By ID
-func (r *UserRepo) UpdateUserByID(c *User) error {
-	res, err := r.DB.Exec("update nguoi_dung set username=?,email=?,age=?,created_at=? where id=?", c.UserName, c.Email, c.Age, c.CreatedAt, c.ID) // Sửa: Thêm khoảng trắng trước 'where'
+func (r *UserRepo) UpdateUserByID(ctx context.Context, c *User) error {
+	ctx, cancel := r.withTimeout(ctx, r.Timeouts.Update)
+	defer cancel()
+
+	res, err := database.Conn(ctx, r.DB).ExecContext(ctx, "update nguoi_dung set username=?,email=?,age=?,created_at=?,updated_at=? where id=?", c.UserName, c.Email, c.Age, c.CreatedAt, c.UpdatedAt, c.ID) // Sửa: Thêm khoảng trắng trước 'where'
+	if err != nil {
+		return translateError(err)
+	}
+	// DSN bật clientFoundRows nên đây là số dòng khớp, không phải số dòng thay đổi
+	aff, err := res.RowsAffected()
 	if err != nil {
 		return err
 	}
-	aff, _ := res.RowsAffected()
 	if aff == 0 {
-		return fmt.Errorf("contact with ID %d not found", c.ID)
+		return ErrUserNotFound
 	}
 	return nil
 }
 
 // Delete By Id
-func (r *UserRepo) DeleteUserByID(id int) error {
-	res, err := r.DB.Exec("Delete from nguoi_dung where id=?", id)
+func (r *UserRepo) DeleteUserByID(ctx context.Context, id int) error {
+	ctx, cancel := r.withTimeout(ctx, r.Timeouts.Delete)
+	defer cancel()
+
+	res, err := database.Conn(ctx, r.DB).ExecContext(ctx, "Delete from nguoi_dung where id=?", id)
 	if err != nil {
 		return err // Sửa: Trả về err
 	}
-	rows, _ := res.RowsAffected()
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
 	if rows == 0 {
-		return fmt.Errorf("contact with ID %d not found", id)
+		return ErrUserNotFound
 	}
 	return nil
 }
-func (r *UserRepo) GetUserByUsername(username string) (*User, error) {
-	row := r.DB.QueryRow("select id,username,email,age,created_at from nguoi_dung where username=?", username)
+func (r *UserRepo) GetUserByUsername(ctx context.Context, username string) (*User, error) {
+	ctx, cancel := r.withTimeout(ctx, r.Timeouts.Get)
+	defer cancel()
+
+	row := database.Conn(ctx, r.DB).QueryRowContext(ctx, "select id,username,email,age,created_at,updated_at from nguoi_dung where username=?", username)
 	var c User
-	if err := row.Scan(&c.ID, &c.UserName, &c.Email, &c.Age, &c.CreatedAt); err != nil {
-		return nil, err // Trả về lỗi (ví dụ: sql.ErrNoRows)
+	if err := row.Scan(&c.ID, &c.UserName, &c.Email, &c.Age, &c.CreatedAt, &c.UpdatedAt); err != nil {
+		return nil, translateError(err) // sql.ErrNoRows → ErrUserNotFound
 	}
 	return &c, nil // Tìm thấy user
 }
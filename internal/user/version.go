@@ -0,0 +1,77 @@
+package user
+
+import "time"
+
+// UserV1 là dạng User của API v1 và các route cũ /user: tên trường Go, không
+// có updated_at. Giữ nguyên để client cũ không bị ảnh hưởng khi User đổi.
+type UserV1 struct {
+	ID        int
+	UserName  string
+	Email     string `log:"mask"`
+	Age       int
+	CreatedAt time.Time
+}
+
+// UserV2 là dạng User của API v2, theo schema UserV2 trong docs.yaml
+type UserV2 struct {
+	ID        int       `json:"id"`
+	UserName  string    `json:"username"`
+	Email     string    `json:"email" log:"mask"`
+	Age       int       `json:"age"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func toV1(u *User) UserV1 {
+	return UserV1{
+		ID:        u.ID,
+		UserName:  u.UserName,
+		Email:     u.Email,
+		Age:       u.Age,
+		CreatedAt: u.CreatedAt,
+	}
+}
+
+func toV1List(users []User) []UserV1 {
+	if users == nil {
+		return nil // v1 trả "data": null khi không có user
+	}
+	out := make([]UserV1, len(users))
+	for i := range users {
+		out[i] = toV1(&users[i])
+	}
+	return out
+}
+
+// patchUser chép các trường khác rỗng của body PUT (mọi phiên bản) lên user;
+// ID, CreatedAt và UpdatedAt không bao giờ lấy từ client
+func patchUser(user *User, userName, email string, age int) {
+	if userName != "" {
+		user.UserName = userName
+	}
+	if email != "" {
+		user.Email = email
+	}
+	if age != 0 {
+		user.Age = age
+	}
+}
+
+func toV2(u *User) UserV2 {
+	return UserV2{
+		ID:        u.ID,
+		UserName:  u.UserName,
+		Email:     u.Email,
+		Age:       u.Age,
+		CreatedAt: u.CreatedAt,
+		UpdatedAt: u.UpdatedAt,
+	}
+}
+
+func toV2List(users []User) []UserV2 {
+	out := make([]UserV2, len(users))
+	for i := range users {
+		out[i] = toV2(&users[i])
+	}
+	return out
+}
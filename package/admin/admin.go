@@ -0,0 +1,100 @@
+// Package admin là server quản trị chạy ở cổng riêng, tách khỏi cổng API:
+// pprof, expvar, build info, cấu hình hiệu lực, thống kê runtime/DB và
+// điều chỉnh mức log.
+package admin
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"net/http"
+	"net/http/pprof"
+	"time"
+
+	"vadilatorgolang/package/config"
+	"vadilatorgolang/package/logger"
+	"vadilatorgolang/package/server"
+)
+
+// ErrInsecure trả về khi server quản trị lắng nghe ngoài localhost mà không có token
+var ErrInsecure = errors.New("admin: admin.token là bắt buộc khi admin.host không phải localhost")
+
+// Options là các phụ thuộc của server quản trị
+type Options struct {
+	// Config là cấu hình hiệu lực, được che trường bí mật trước khi trả về
+	Config config.Config
+	// DB là pool kết nối cần xem thống kê; nil = bỏ qua
+	DB *sql.DB
+	// Health trả báo cáo health check kèm lỗi chi tiết; nil = bỏ qua
+	Health http.Handler
+}
+
+// endpoints được liệt kê ở trang chủ của server quản trị
+var endpoints = map[string]string{
+	"/debug/pprof/": "net/http/pprof (profile, heap, goroutine?debug=2, trace...)",
+	"/debug/vars":   "expvar",
+	"/buildinfo":    "phiên bản, commit và phiên bản Go",
+	"/config":       "cấu hình hiệu lực (YAML, đã che bí mật)",
+	"/stats":        "goroutine, bộ nhớ và pool kết nối database",
+	"/health":       "health check kèm lỗi chi tiết",
+	"/log-level":    "xem (GET), đặt (PUT) hoặc bỏ (DELETE) mức log",
+}
+
+// NewHandler tạo router của server quản trị. Khi opts.Config.Admin.Token
+// khác rỗng mọi endpoint đều yêu cầu "Authorization: Bearer <token>".
+func NewHandler(opts Options) http.Handler {
+	r := server.NewMux()
+	r.Use(server.RequestID(), server.Recover(false))
+	if token := opts.Config.Admin.Token; token != "" {
+		r.Use(func(next http.Handler) http.Handler { return server.AdminAuth(token, next) })
+	}
+
+	r.HandleFunc("GET /{$}", func(w http.ResponseWriter, req *http.Request) {
+		writeJSON(w, endpoints)
+	})
+
+	r.HandleFunc("/debug/pprof/", pprof.Index)
+	r.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	r.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	r.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	r.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	r.Handle("GET /debug/vars", expvar.Handler())
+
+	r.HandleFunc("GET /buildinfo", func(w http.ResponseWriter, req *http.Request) {
+		writeJSON(w, ReadBuildInfo())
+	})
+	r.HandleFunc("GET /config", func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/yaml; charset=utf-8")
+		w.Write([]byte(opts.Config.String()))
+	})
+	r.HandleFunc("GET /stats", func(w http.ResponseWriter, req *http.Request) {
+		writeJSON(w, ReadStats(opts.DB))
+	})
+	if opts.Health != nil {
+		r.Handle("GET /health", opts.Health)
+	}
+	r.Handle("/log-level", logger.LevelHandler())
+	return r
+}
+
+// NewServer tạo http.Server cho server quản trị. Không đặt WriteTimeout vì
+// /debug/pprof/profile và /debug/pprof/trace ghi kết quả sau nhiều giây.
+func NewServer(cfg config.AdminConfig, handler http.Handler) (*http.Server, error) {
+	if cfg.Token == "" && !cfg.Loopback() {
+		return nil, ErrInsecure
+	}
+	return &http.Server{
+		Addr:              cfg.Addr(),
+		Handler:           handler,
+		ReadHeaderTimeout: 5 * time.Second,
+		IdleTimeout:       time.Minute,
+	}, nil
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
@@ -0,0 +1,134 @@
+package admin
+
+import (
+	"database/sql"
+	"runtime"
+	"runtime/debug"
+	"time"
+)
+
+// Version và Commit có thể gán lúc build:
+//
+//	go build -ldflags "-X vadilatorgolang/package/admin.Version=v1.2.0 -X vadilatorgolang/package/admin.Commit=abc123" ./cmd
+//
+// Để trống thì lấy từ thông tin module và VCS mà Go ghi vào binary.
+var (
+	Version string
+	Commit  string
+)
+
+// startTime là thời điểm tiến trình khởi động, dùng tính uptime
+var startTime = time.Now()
+
+// BuildInfo là kết quả của /buildinfo
+type BuildInfo struct {
+	Version    string `json:"version"`
+	Commit     string `json:"commit,omitempty"`
+	CommitTime string `json:"commit_time,omitempty"`
+	Modified   bool   `json:"modified"`
+	GoVersion  string `json:"go_version"`
+	Path       string `json:"path,omitempty"`
+	OS         string `json:"os"`
+	Arch       string `json:"arch"`
+}
+
+// ReadBuildInfo đọc thông tin build từ debug.ReadBuildInfo, ưu tiên
+// Version/Commit gán qua -ldflags
+func ReadBuildInfo() BuildInfo {
+	info := BuildInfo{
+		Version:   Version,
+		Commit:    Commit,
+		GoVersion: runtime.Version(),
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+	}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		info.Path = bi.Main.Path
+		if info.Version == "" {
+			info.Version = bi.Main.Version
+		}
+		for _, s := range bi.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				if info.Commit == "" {
+					info.Commit = s.Value
+				}
+			case "vcs.time":
+				info.CommitTime = s.Value
+			case "vcs.modified":
+				info.Modified = s.Value == "true"
+			}
+		}
+	}
+	if info.Version == "" {
+		info.Version = "(devel)"
+	}
+	return info
+}
+
+// Stats là kết quả của /stats
+type Stats struct {
+	Uptime     string      `json:"uptime"`
+	Goroutines int         `json:"goroutines"`
+	GOMAXPROCS int         `json:"gomaxprocs"`
+	NumCPU     int         `json:"num_cpu"`
+	Memory     MemoryStats `json:"memory"`
+	DB         *DBStats    `json:"db,omitempty"`
+}
+
+type MemoryStats struct {
+	HeapAllocBytes uint64  `json:"heap_alloc_bytes"`
+	HeapInuseBytes uint64  `json:"heap_inuse_bytes"`
+	SysBytes       uint64  `json:"sys_bytes"`
+	NumGC          uint32  `json:"num_gc"`
+	GCPauseTotal   string  `json:"gc_pause_total"`
+	GCCPUFraction  float64 `json:"gc_cpu_fraction"`
+}
+
+// DBStats là sql.DBStats với tên trường dạng JSON
+type DBStats struct {
+	MaxOpenConnections int    `json:"max_open_connections"`
+	OpenConnections    int    `json:"open_connections"`
+	InUse              int    `json:"in_use"`
+	Idle               int    `json:"idle"`
+	WaitCount          int64  `json:"wait_count"`
+	WaitDuration       string `json:"wait_duration"`
+	MaxIdleClosed      int64  `json:"max_idle_closed"`
+	MaxIdleTimeClosed  int64  `json:"max_idle_time_closed"`
+	MaxLifetimeClosed  int64  `json:"max_lifetime_closed"`
+}
+
+// ReadStats chụp số liệu runtime hiện tại; db nil thì bỏ phần DB
+func ReadStats(db *sql.DB) Stats {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	s := Stats{
+		Uptime:     time.Since(startTime).Round(time.Second).String(),
+		Goroutines: runtime.NumGoroutine(),
+		GOMAXPROCS: runtime.GOMAXPROCS(0),
+		NumCPU:     runtime.NumCPU(),
+		Memory: MemoryStats{
+			HeapAllocBytes: ms.HeapAlloc,
+			HeapInuseBytes: ms.HeapInuse,
+			SysBytes:       ms.Sys,
+			NumGC:          ms.NumGC,
+			GCPauseTotal:   time.Duration(ms.PauseTotalNs).String(),
+			GCCPUFraction:  ms.GCCPUFraction,
+		},
+	}
+	if db != nil {
+		d := db.Stats()
+		s.DB = &DBStats{
+			MaxOpenConnections: d.MaxOpenConnections,
+			OpenConnections:    d.OpenConnections,
+			InUse:              d.InUse,
+			Idle:               d.Idle,
+			WaitCount:          d.WaitCount,
+			WaitDuration:       d.WaitDuration.String(),
+			MaxIdleClosed:      d.MaxIdleClosed,
+			MaxIdleTimeClosed:  d.MaxIdleTimeClosed,
+			MaxLifetimeClosed:  d.MaxLifetimeClosed,
+		}
+	}
+	return s
+}
@@ -0,0 +1,142 @@
+package apperr
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Kind phân loại lỗi, quyết định HTTP status khi trả về client
+type Kind uint8
+
+const (
+	KindInternal Kind = iota
+	KindBadRequest
+	KindValidation
+	KindUnauthorized
+	KindForbidden
+	KindNotFound
+	KindMethodNotAllowed
+	KindConflict
+	KindCanceled
+	KindTimeout
+	KindUnavailable
+	KindTooLarge
+	KindTooManyRequests
+	KindUnsupportedMediaType
+	KindNotAcceptable
+)
+
+var kindNames = map[Kind]string{
+	KindInternal:             "internal",
+	KindBadRequest:           "bad_request",
+	KindValidation:           "validation",
+	KindUnauthorized:         "unauthorized",
+	KindForbidden:            "forbidden",
+	KindNotFound:             "not_found",
+	KindMethodNotAllowed:     "method_not_allowed",
+	KindConflict:             "conflict",
+	KindCanceled:             "canceled",
+	KindTimeout:              "timeout",
+	KindUnavailable:          "unavailable",
+	KindTooLarge:             "too_large",
+	KindTooManyRequests:      "too_many_requests",
+	KindUnsupportedMediaType: "unsupported_media_type",
+	KindNotAcceptable:        "not_acceptable",
+}
+
+func (k Kind) String() string {
+	if name, ok := kindNames[k]; ok {
+		return name
+	}
+	return fmt.Sprintf("kind(%d)", k)
+}
+
+// Error là lỗi có phân loại. Message an toàn để trả cho client,
+// Err là nguyên nhân gốc chỉ dùng để ghi log.
+type Error struct {
+	Kind    Kind
+	Code    string // mã ổn định cho máy đọc, ví dụ "user_not_found"
+	Message string
+	Fields  []FieldError // chi tiết lỗi theo từng trường (validation)
+	Err     error
+}
+
+// FieldError mô tả một trường vi phạm quy tắc validate
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// Is so khớp theo Kind + Code để errors.Is(err, ErrUserNotFound) vẫn đúng
+// sau khi lỗi đã được Wrap thêm nguyên nhân
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok {
+		return false
+	}
+	return e.Kind == t.Kind && e.Code == t.Code
+}
+
+// Wrap trả về bản sao của e với nguyên nhân gốc err
+func (e *Error) Wrap(err error) *Error {
+	cp := *e
+	cp.Err = err
+	return &cp
+}
+
+// WithFields trả về bản sao của e kèm chi tiết theo trường
+func (e *Error) WithFields(fields []FieldError) *Error {
+	cp := *e
+	cp.Fields = fields
+	return &cp
+}
+
+// New tạo lỗi mới với kind, code và message cho client
+func New(kind Kind, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+// Wrap bọc err thành lỗi có phân loại
+func Wrap(err error, kind Kind, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message, Err: err}
+}
+
+func BadRequest(code, message string) *Error      { return New(KindBadRequest, code, message) }
+func Validation(code, message string) *Error      { return New(KindValidation, code, message) }
+func Unauthorized(code, message string) *Error    { return New(KindUnauthorized, code, message) }
+func Forbidden(code, message string) *Error       { return New(KindForbidden, code, message) }
+func NotFound(code, message string) *Error        { return New(KindNotFound, code, message) }
+func Conflict(code, message string) *Error        { return New(KindConflict, code, message) }
+func Internal(code, message string) *Error        { return New(KindInternal, code, message) }
+func TooLarge(code, message string) *Error        { return New(KindTooLarge, code, message) }
+func TooManyRequests(code, message string) *Error { return New(KindTooManyRequests, code, message) }
+func UnsupportedMediaType(code, message string) *Error {
+	return New(KindUnsupportedMediaType, code, message)
+}
+func NotAcceptable(code, message string) *Error { return New(KindNotAcceptable, code, message) }
+
+// As lấy *Error trong chuỗi lỗi, nếu có
+func As(err error) (*Error, bool) {
+	var e *Error
+	ok := errors.As(err, &e)
+	return e, ok
+}
+
+// KindOf trả về Kind của err; lỗi không phân loại được coi là KindInternal
+func KindOf(err error) Kind {
+	if e, ok := As(err); ok {
+		return e.Kind
+	}
+	return KindInternal
+}
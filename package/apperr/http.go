@@ -0,0 +1,114 @@
+package apperr
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"vadilatorgolang/package/requestid"
+)
+
+// StatusClientClosedRequest (499, theo nginx) dùng khi client ngắt kết nối trước khi có response
+const StatusClientClosedRequest = 499
+
+// ProblemContentType là media type của RFC 7807
+const ProblemContentType = "application/problem+json"
+
+// ProblemTypeBase là tiền tố của trường "type"; type = ProblemTypeBase + Code
+var ProblemTypeBase = "/problems/"
+
+var kindStatus = map[Kind]int{
+	KindInternal:             http.StatusInternalServerError,
+	KindBadRequest:           http.StatusBadRequest,
+	KindValidation:           http.StatusBadRequest,
+	KindUnauthorized:         http.StatusUnauthorized,
+	KindForbidden:            http.StatusForbidden,
+	KindNotFound:             http.StatusNotFound,
+	KindMethodNotAllowed:     http.StatusMethodNotAllowed,
+	KindConflict:             http.StatusConflict,
+	KindCanceled:             StatusClientClosedRequest,
+	KindTimeout:              http.StatusGatewayTimeout,
+	KindUnavailable:          http.StatusServiceUnavailable,
+	KindTooLarge:             http.StatusRequestEntityTooLarge,
+	KindTooManyRequests:      http.StatusTooManyRequests,
+	KindUnsupportedMediaType: http.StatusUnsupportedMediaType,
+	KindNotAcceptable:        http.StatusNotAcceptable,
+}
+
+// HTTPStatus trả về HTTP status tương ứng với Kind
+func (k Kind) HTTPStatus() int {
+	if status, ok := kindStatus[k]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// Problem là body lỗi theo RFC 7807 (application/problem+json)
+type Problem struct {
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Status    int    `json:"status"`
+	Detail    string `json:"detail,omitempty"`
+	Instance  string `json:"instance,omitempty"`
+	Code      string `json:"code"`
+	RequestID string `json:"request_id,omitempty"`
+	// IncidentID chỉ có với lỗi không lường trước (panic), để đối chiếu với log lỗi
+	IncidentID string       `json:"incident_id,omitempty"`
+	Errors     []FieldError `json:"errors,omitempty"`
+}
+
+// Translate chuyển một lỗi bất kỳ thành *Error an toàn để trả cho client.
+// ctx là context của request, dùng để phân biệt client tự hủy (499) với
+// server hủy (503). Lỗi không nhận diện được thành Internal, nội dung gốc
+// chỉ nằm trong Err để ghi log, không lộ ra ngoài.
+func Translate(ctx context.Context, err error) *Error {
+	if e, ok := As(err); ok {
+		return e
+	}
+	switch {
+	case errors.Is(err, context.Canceled) && ctx != nil && ctx.Err() != nil:
+		return Wrap(err, KindCanceled, "request_canceled", "Request đã bị hủy")
+	case errors.Is(err, context.DeadlineExceeded):
+		return Wrap(err, KindTimeout, "timeout", "Truy vấn quá thời gian cho phép")
+	case errors.Is(err, context.Canceled), errors.Is(err, driver.ErrBadConn), errors.Is(err, sql.ErrConnDone):
+		return Wrap(err, KindUnavailable, "service_unavailable", "Dịch vụ tạm thời không khả dụng")
+	}
+	return Wrap(err, KindInternal, "internal_error", "Lỗi hệ thống, vui lòng thử lại sau")
+}
+
+// NewProblem dựng Problem cho lỗi e xảy ra khi xử lý r
+func NewProblem(r *http.Request, e *Error) Problem {
+	status := e.Kind.HTTPStatus()
+	title := http.StatusText(status)
+	if title == "" {
+		title = e.Kind.String()
+	}
+	return Problem{
+		Type:      ProblemTypeBase + e.Code,
+		Title:     title,
+		Status:    status,
+		Detail:    e.Message,
+		Instance:  r.URL.Path,
+		Code:      e.Code,
+		RequestID: requestid.FromRequest(r),
+		Errors:    e.Fields,
+	}
+}
+
+// WriteProblem dịch err rồi ghi response application/problem+json ra w,
+// trả về lỗi đã dịch để nơi gọi ghi log
+func WriteProblem(w http.ResponseWriter, r *http.Request, err error) *Error {
+	e := Translate(r.Context(), err)
+	NewProblem(r, e).Write(w)
+	return e
+}
+
+// Write ghi p ra w với Content-Type application/problem+json
+func (p Problem) Write(w http.ResponseWriter) {
+	w.Header().Set("content-type", ProblemContentType)
+	w.WriteHeader(p.Status)
+	json.NewEncoder(w).Encode(p)
+}
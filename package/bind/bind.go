@@ -0,0 +1,261 @@
+package bind
+
+import (
+	"encoding"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"vadilatorgolang/package/apperr"
+	customValidator "vadilatorgolang/package/validator"
+)
+
+// Bind tạo T từ request rồi validate. Nguồn của từng trường lấy theo tag:
+//
+//	path:"id"          r.PathValue("id")
+//	query:"limit"      tham số query; trường slice nhận mọi giá trị lặp lại
+//	header:"X-Tenant"  header của request
+//	default:"50"       giá trị dùng khi nguồn trên không có
+//
+// Các trường còn lại lấy từ body JSON (giải mã chặt như JSON) nếu request có
+// body; với POST/PUT/PATCH body là bắt buộc. Kích thước body do middleware
+// BodyLimit giới hạn như với JSON. Trường có tag path/query/header
+// nên kèm json:"-" để không bị nhận nhầm từ body.
+// Lỗi chuyển kiểu và lỗi validate được gộp thành một
+// customValidator.ErrValidation (cùng lỗi với ValidateStruct); lỗi của
+// body (415, 413, JSON sai) được trả về ngay.
+func Bind[T any](r *http.Request) (T, error) {
+	var v T
+	err := Into(r, &v)
+	return v, err
+}
+
+// Into giống Bind nhưng ghi vào dst có sẵn (con trỏ tới struct)
+func Into(r *http.Request, dst any) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Pointer || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("bind: dst phải là con trỏ tới struct, nhận %T", dst)
+	}
+
+	if hasBodyFields(rv.Elem().Type()) && (hasBody(r) || expectsBody(r)) {
+		if err := decodeBody(r, dst, 0); err != nil {
+			return err
+		}
+	}
+
+	var fields []apperr.FieldError
+	bad := map[string]bool{}
+	walk(rv.Elem(), func(f reflect.StructField, v reflect.Value) {
+		name, values := lookup(r, f)
+		if values == nil {
+			return
+		}
+		if err := setField(v, values); err != nil {
+			bad[name] = true
+			fields = append(fields, apperr.FieldError{
+				Field:   name,
+				Rule:    "type",
+				Param:   typeName(v.Type()),
+				Message: fmt.Sprintf("Trường '%s' phải có kiểu %s: %v", name, typeName(v.Type()), err),
+			})
+		}
+	})
+
+	if err := validate(r, dst); err != nil {
+		vf, ok := customValidator.FieldErrors(err)
+		if !ok {
+			return customValidator.ToAppError(err)
+		}
+		// Trường đã sai kiểu thì lỗi validate của nó (thường là required) chỉ gây nhiễu
+		for _, fe := range vf {
+			if !bad[fe.Field] {
+				fields = append(fields, fe)
+			}
+		}
+	}
+	if len(fields) > 0 {
+		return customValidator.ErrValidation.WithFields(fields)
+	}
+	return nil
+}
+
+// hasBody báo request có body cần đọc
+func hasBody(r *http.Request) bool {
+	return r.Body != nil && r.Body != http.NoBody && r.ContentLength != 0
+}
+
+// expectsBody báo method này bắt buộc có body khi T có trường lấy từ body,
+// để PUT thiếu body không âm thầm thành cập nhật rỗng
+func expectsBody(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch:
+		return true
+	}
+	return false
+}
+
+// hasBodyFields báo t có trường nào lấy từ body (không có tag path/query/header)
+func hasBodyFields(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() || f.Tag.Get("json") == "-" {
+			continue
+		}
+		if _, ok := sourceTag(f); ok {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// sourceTag trả về tag nguồn của trường ngoài body, nếu có
+func sourceTag(f reflect.StructField) (string, bool) {
+	for _, key := range []string{"path", "query", "header"} {
+		if _, ok := f.Tag.Lookup(key); ok {
+			return key, true
+		}
+	}
+	return "", false
+}
+
+// walk duyệt các trường có tag nguồn, kể cả trong struct nhúng
+func walk(v reflect.Value, fn func(reflect.StructField, reflect.Value)) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f, fv := t.Field(i), v.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		if _, ok := sourceTag(f); ok {
+			fn(f, fv)
+			continue
+		}
+		if f.Anonymous && fv.Kind() == reflect.Struct {
+			walk(fv, fn)
+		}
+	}
+}
+
+// lookup lấy tên và các giá trị thô của trường từ request; nil = không có giá trị
+func lookup(r *http.Request, f reflect.StructField) (string, []string) {
+	src, _ := sourceTag(f)
+	name := f.Tag.Get(src)
+	var values []string
+	switch src {
+	case "path":
+		if v := r.PathValue(name); v != "" {
+			values = []string{v}
+		}
+	case "query":
+		values = r.URL.Query()[name]
+	case "header":
+		values = r.Header.Values(name)
+	}
+	if len(values) == 0 {
+		if def, ok := f.Tag.Lookup("default"); ok {
+			values = []string{def}
+		}
+	}
+	return name, values
+}
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	timeType            = reflect.TypeOf(time.Time{})
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// setField gán values vào v; slice nhận tất cả, kiểu khác nhận giá trị đầu
+func setField(v reflect.Value, values []string) error {
+	if v.Kind() == reflect.Slice && v.Type().Elem().Kind() != reflect.Uint8 {
+		s := reflect.MakeSlice(v.Type(), len(values), len(values))
+		for i, raw := range values {
+			if err := setValue(s.Index(i), raw); err != nil {
+				return err
+			}
+		}
+		v.Set(s)
+		return nil
+	}
+	return setValue(v, values[0])
+}
+
+func setValue(v reflect.Value, raw string) error {
+	if v.Kind() == reflect.Pointer {
+		p := reflect.New(v.Type().Elem())
+		if err := setValue(p.Elem(), raw); err != nil {
+			return err
+		}
+		v.Set(p)
+		return nil
+	}
+	if reflect.PointerTo(v.Type()).Implements(textUnmarshalerType) && v.Type() != timeType {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(raw))
+	}
+
+	switch {
+	case v.Type() == timeType:
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return fmt.Errorf("cần định dạng RFC 3339")
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	case v.Type() == durationType:
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("%q không phải true/false", raw)
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(strings.TrimSpace(raw), 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%q không phải số nguyên hợp lệ", raw)
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(strings.TrimSpace(raw), 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%q không phải số nguyên không âm hợp lệ", raw)
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(strings.TrimSpace(raw), v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%q không phải số hợp lệ", raw)
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("kiểu %s không được hỗ trợ", v.Type())
+	}
+	return nil
+}
+
+// typeName là tên kiểu thân thiện cho client
+func typeName(t reflect.Type) string {
+	switch {
+	case t == timeType:
+		return "time"
+	case t == durationType:
+		return "duration"
+	case t.Kind() == reflect.Pointer, t.Kind() == reflect.Slice:
+		return typeName(t.Elem())
+	}
+	return t.Kind().String()
+}
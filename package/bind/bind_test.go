@@ -0,0 +1,116 @@
+package bind
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"vadilatorgolang/package/apperr"
+	customValidator "vadilatorgolang/package/validator"
+)
+
+type bindParams struct {
+	ID      int           `path:"id" validate:"gt=0"`
+	Limit   int           `query:"limit" default:"50" validate:"lte=200"`
+	Tags    []string      `query:"tag"`
+	Since   *time.Time    `query:"since"`
+	Wait    time.Duration `query:"wait"`
+	Debug   bool          `query:"debug"`
+	Ratio   float64       `query:"ratio"`
+	Tenant  string        `header:"X-Tenant"`
+	Retries uint8         `header:"X-Retries"`
+}
+
+type bindBody struct {
+	ID   int    `path:"id" json:"-" validate:"gt=0"`
+	Name string `json:"name" validate:"required"`
+}
+
+func TestBindConversion(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/x?tag=a&tag=b&since=2026-01-02T03:04:05Z&wait=1m30s&debug=true&ratio=0.5", nil)
+	r.SetPathValue("id", "7")
+	r.Header.Set("X-Tenant", "acme")
+	r.Header.Set("X-Retries", " 3 ")
+
+	got, err := Bind[bindParams](r)
+	if err != nil {
+		t.Fatalf("Bind: %v", err)
+	}
+	since := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	want := bindParams{
+		ID: 7, Limit: 50, Tags: []string{"a", "b"}, Since: &since, Wait: 90 * time.Second,
+		Debug: true, Ratio: 0.5, Tenant: "acme", Retries: 3,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Bind = %+v\nmuốn %+v", got, want)
+	}
+}
+
+func TestBindErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		id     string
+		fields map[string]string // field -> rule
+	}{
+		{"sai kiểu gộp với validate", "/x?limit=abc&since=hôm-qua", "0", map[string]string{"limit": "type", "since": "type", "id": "gt"}},
+		{"default vẫn qua validate", "/x?limit=500", "1", map[string]string{"limit": "lte"}},
+		{"path không phải số", "/x", "abc", map[string]string{"id": "type"}},
+		{"bool sai", "/x?debug=maybe", "1", map[string]string{"debug": "type"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			r.SetPathValue("id", tt.id)
+			_, err := Bind[bindParams](r)
+			if !errors.Is(err, customValidator.ErrValidation) {
+				t.Fatalf("err = %v, muốn ErrValidation", err)
+			}
+			e, _ := apperr.As(err)
+			got := map[string]string{}
+			for _, fe := range e.Fields {
+				got[fe.Field] = fe.Rule
+			}
+			if !reflect.DeepEqual(got, tt.fields) {
+				t.Errorf("fields = %v, muốn %v", got, tt.fields)
+			}
+		})
+	}
+}
+
+func TestBindBody(t *testing.T) {
+	newReq := func(method, body string) *http.Request {
+		r := httptest.NewRequest(method, "/user/3", strings.NewReader(body))
+		if body != "" {
+			r.Header.Set("Content-Type", "application/json")
+		}
+		r.SetPathValue("id", "3")
+		return r
+	}
+
+	got, err := Bind[bindBody](newReq(http.MethodPut, `{"name":"an"}`))
+	if err != nil || got.ID != 3 || got.Name != "an" {
+		t.Errorf("Bind = %+v, %v", got, err)
+	}
+
+	// id trong body không được ghi đè id trên đường dẫn
+	_, err = Bind[bindBody](newReq(http.MethodPut, `{"name":"an","ID":9}`))
+	if e, ok := apperr.As(err); !ok || e.Code != "invalid_json" || e.Fields[0].Field != "ID" {
+		t.Errorf("ID trong body: err = %v", err)
+	}
+
+	// PUT thiếu body phải báo lỗi thay vì cập nhật rỗng
+	if _, err := Bind[bindBody](newReq(http.MethodPut, "")); err == nil {
+		t.Error("PUT không có body: muốn lỗi")
+	}
+
+	// GET không có body chỉ chạy validate
+	_, err = Bind[bindBody](newReq(http.MethodGet, ""))
+	if e, ok := apperr.As(err); !ok || len(e.Fields) != 1 || e.Fields[0].Rule != "required" {
+		t.Errorf("GET không có body: err = %v", err)
+	}
+}
@@ -0,0 +1,222 @@
+// Package bind đọc dữ liệu của request vào struct một cách chặt chẽ và
+// trả về lỗi apperr sẵn sàng ghi ra client.
+package bind
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"vadilatorgolang/package/apperr"
+	"vadilatorgolang/package/tracing"
+	customValidator "vadilatorgolang/package/validator"
+)
+
+var (
+	ErrUnsupportedMediaType = apperr.UnsupportedMediaType("unsupported_media_type", "Content-Type phải là application/json")
+	ErrBodyTooLarge         = apperr.TooLarge("body_too_large", "Body của request vượt quá kích thước cho phép")
+	ErrEmptyBody            = apperr.BadRequest("empty_body", "Body của request không được rỗng")
+	ErrInvalidJSON          = apperr.BadRequest("invalid_json", "Body không phải JSON hợp lệ")
+)
+
+// JSON giải mã body JSON của r vào dst (con trỏ tới struct) rồi chạy
+// ValidateStruct. Kích thước body do middleware BodyLimit giới hạn theo
+// server.max_body_bytes (vượt thì vẫn trả 413); xem JSONLimit.
+func JSON(r *http.Request, dst any) error {
+	return JSONLimit(r, dst, 0)
+}
+
+// JSONLimit giống JSON với giới hạn body maxBytes (<= 0 = không giới hạn):
+//   - Content-Type khác application/json (hoặc application/*+json): 415
+//   - body vượt maxBytes: 413
+//   - body rỗng, sai cú pháp, sai kiểu, có trường lạ hoặc nhiều hơn một
+//     giá trị JSON: 400, kèm trường và vị trí byte gây lỗi
+//   - dữ liệu vi phạm tag validate: 400 validation_failed
+func JSONLimit(r *http.Request, dst any, maxBytes int64) error {
+	if err := decodeBody(r, dst, maxBytes); err != nil {
+		return err
+	}
+	if err := validate(r, dst); err != nil {
+		return customValidator.ToAppError(err)
+	}
+	return nil
+}
+
+// validate chạy ValidateStruct trong một span riêng
+func validate(r *http.Request, dst any) (err error) {
+	_, span := tracing.Start(r.Context(), "validator.ValidateStruct",
+		tracing.String("validator.type", fmt.Sprintf("%T", dst)))
+	defer span.Finish(&err)
+	return customValidator.ValidateStruct(dst)
+}
+
+// decodeBody là phần giải mã chặt của JSONLimit, chưa validate
+func decodeBody(r *http.Request, dst any, maxBytes int64) (err error) {
+	_, span := tracing.Start(r.Context(), "bind.decode")
+	defer span.Finish(&err)
+
+	if err := requireJSON(r); err != nil {
+		return err
+	}
+	if maxBytes > 0 && r.ContentLength > maxBytes {
+		return ErrBodyTooLarge
+	}
+
+	// Đọc hết body (đã giới hạn) trước khi giải mã để tính được vị trí byte
+	// chính xác của lỗi
+	body := r.Body
+	if maxBytes > 0 {
+		body = http.MaxBytesReader(nil, r.Body, maxBytes)
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			return ErrBodyTooLarge.Wrap(err)
+		}
+		return ErrInvalidJSON.Wrap(err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(dst); err != nil {
+		return decodeError(err, data)
+	}
+	// Chỉ nhận đúng một giá trị JSON, phần còn lại chỉ được là khoảng trắng
+	end := dec.InputOffset()
+	if err := dec.Decode(&struct{}{}); !errors.Is(err, io.EOF) {
+		if err == nil || !isSyntax(err) {
+			at := end + int64(len(data[end:])-len(bytes.TrimLeft(data[end:], " \t\r\n")))
+			return ErrInvalidJSON.WithFields([]apperr.FieldError{{
+				Rule:    "single_value",
+				Param:   fmt.Sprint(at),
+				Message: fmt.Sprintf("Body chỉ được chứa một giá trị JSON, có dữ liệu thừa tại byte %d", at),
+			}}).Wrap(err)
+		}
+		return decodeError(err, data)
+	}
+	return nil
+}
+
+// requireJSON chấp nhận application/json và các kiểu application/...+json
+func requireJSON(r *http.Request) error {
+	ct := r.Header.Get("Content-Type")
+	if ct == "" {
+		return ErrUnsupportedMediaType.Wrap(errors.New("thiếu Content-Type"))
+	}
+	mt, _, err := mime.ParseMediaType(ct)
+	if err != nil {
+		return ErrUnsupportedMediaType.Wrap(err)
+	}
+	if mt == "application/json" || (strings.HasPrefix(mt, "application/") && strings.HasSuffix(mt, "+json")) {
+		return nil
+	}
+	return ErrUnsupportedMediaType.Wrap(fmt.Errorf("Content-Type = %q", mt))
+}
+
+func isSyntax(err error) bool {
+	var se *json.SyntaxError
+	return errors.As(err, &se) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// decodeError dịch lỗi của encoding/json thành lỗi 400 có chi tiết; data là
+// toàn bộ body, dùng để tính vị trí byte gây lỗi.
+func decodeError(err error, data []byte) error {
+	var (
+		syntaxErr *json.SyntaxError
+		typeErr   *json.UnmarshalTypeError
+	)
+	switch {
+	case errors.Is(err, io.EOF):
+		return ErrEmptyBody.Wrap(err)
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return invalid(err, apperr.FieldError{
+			Rule:    "syntax",
+			Param:   fmt.Sprint(len(data)),
+			Message: fmt.Sprintf("JSON bị cắt ngang tại byte %d, thiếu phần kết thúc", len(data)),
+		})
+	case errors.As(err, &syntaxErr):
+		// Offset là số byte đã đọc, byte gây lỗi là byte cuối cùng trong số đó
+		at := max(syntaxErr.Offset-1, 0)
+		return invalid(err, apperr.FieldError{
+			Rule:    "syntax",
+			Param:   fmt.Sprint(at),
+			Message: fmt.Sprintf("JSON sai cú pháp tại byte %d: %s", at, syntaxErr.Error()),
+		})
+	case errors.As(err, &typeErr):
+		return invalid(err, apperr.FieldError{
+			Field:   typeErr.Field,
+			Rule:    "type",
+			Param:   typeErr.Type.String(),
+			Message: fmt.Sprintf("Trường '%s' phải có kiểu %s, nhận được %s (byte %d)", typeErr.Field, typeErr.Type, typeErr.Value, typeErr.Offset),
+		})
+	}
+	// encoding/json không có kiểu lỗi riêng cho trường lạ
+	if name, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+		field, uerr := strconv.Unquote(name)
+		if uerr != nil {
+			field = strings.Trim(name, `"`)
+		}
+		fe := apperr.FieldError{
+			Field:   field,
+			Rule:    "unknown",
+			Message: fmt.Sprintf("Trường '%s' không được hỗ trợ", field),
+		}
+		if at, ok := keyOffset(data, field); ok {
+			fe.Param = fmt.Sprint(at)
+			fe.Message = fmt.Sprintf("Trường '%s' không được hỗ trợ (byte %d)", field, at)
+		}
+		return invalid(err, fe)
+	}
+	return ErrInvalidJSON.Wrap(err)
+}
+
+// keyOffset trả về vị trí byte (ngay sau dấu ") của khóa object đầu tiên
+// bằng name trong data. Decoder sau Decode lỗi chỉ biết vị trí cuối giá trị,
+// nên phải duyệt lại token để tìm.
+func keyOffset(data []byte, name string) (int64, bool) {
+	type frame struct{ object, wantKey bool }
+	var stack []frame
+	dec := json.NewDecoder(bytes.NewReader(data))
+	for {
+		prev := dec.InputOffset()
+		tok, err := dec.Token()
+		if err != nil {
+			return 0, false
+		}
+		top := len(stack) - 1
+		if top >= 0 && stack[top].object && stack[top].wantKey {
+			if key, ok := tok.(string); ok {
+				if key == name {
+					return prev + int64(bytes.IndexByte(data[prev:], '"')) + 1, true
+				}
+				stack[top].wantKey = false
+				continue
+			}
+		}
+		switch tok {
+		case json.Delim('{'):
+			stack = append(stack, frame{object: true, wantKey: true})
+			continue
+		case json.Delim('['):
+			stack = append(stack, frame{})
+			continue
+		case json.Delim('}'), json.Delim(']'):
+			stack = stack[:top]
+		}
+		// Vừa xong một giá trị: trong object thì tiếp theo là khóa
+		if n := len(stack); n > 0 && stack[n-1].object {
+			stack[n-1].wantKey = true
+		}
+	}
+}
+
+func invalid(err error, fe apperr.FieldError) error {
+	return ErrInvalidJSON.WithFields([]apperr.FieldError{fe}).Wrap(err)
+}
@@ -0,0 +1,126 @@
+package bind
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"vadilatorgolang/package/apperr"
+)
+
+type jsonTarget struct {
+	Name  string `json:"name"`
+	Age   int    `json:"age"`
+	Inner struct {
+		City string `json:"city"`
+	} `json:"inner"`
+	Tags []struct {
+		Key string `json:"key"`
+	} `json:"tags"`
+}
+
+func newJSONRequest(body string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	return r
+}
+
+func TestJSONErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		body  string
+		code  string
+		field string
+		rule  string
+		param string
+	}{
+		{"empty", ``, "empty_body", "", "", ""},
+		{"syntax", `{"name":}`, "invalid_json", "", "syntax", "8"},
+		{"truncated", `{"name":"a"`, "invalid_json", "", "syntax", "11"},
+		{"type", `{"age":"x"}`, "invalid_json", "age", "type", "int"},
+		{"unknown field", `{"name":"a","zzz":1,"age":20,"inner":{"city":"hn"}}`, "invalid_json", "zzz", "unknown", "13"},
+		{"unknown nested", `{"inner":{"city":"hn","zip":1}}`, "invalid_json", "zip", "unknown", "23"},
+		{"unknown in array", `{"tags":[{"key":"a"},{"k2":1}]}`, "invalid_json", "k2", "unknown", "23"},
+		{"unknown after value named like key", `{"name":"zzz","zzz":1}`, "invalid_json", "zzz", "unknown", "15"},
+		{"trailing value", `{"name":"a"} {"b":1}`, "invalid_json", "", "single_value", "13"},
+		{"trailing garbage", "{\"name\":\"a\"}\n\tx", "invalid_json", "", "syntax", "14"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var dst jsonTarget
+			err := JSON(newJSONRequest(tt.body), &dst)
+			e, ok := apperr.As(err)
+			if !ok {
+				t.Fatalf("err = %v, muốn *apperr.Error", err)
+			}
+			if e.Code != tt.code {
+				t.Fatalf("code = %q, muốn %q (%v)", e.Code, tt.code, err)
+			}
+			if tt.rule == "" {
+				return
+			}
+			if len(e.Fields) != 1 {
+				t.Fatalf("fields = %+v, muốn đúng một", e.Fields)
+			}
+			fe := e.Fields[0]
+			if fe.Field != tt.field || fe.Rule != tt.rule || fe.Param != tt.param {
+				t.Errorf("field = %+v, muốn field=%q rule=%q param=%q", fe, tt.field, tt.rule, tt.param)
+			}
+		})
+	}
+}
+
+func TestJSONContentTypeAndSize(t *testing.T) {
+	r := newJSONRequest(`{}`)
+	r.Header.Set("Content-Type", "text/plain")
+	if err := JSON(r, &jsonTarget{}); !errors.Is(err, ErrUnsupportedMediaType) {
+		t.Errorf("text/plain: err = %v", err)
+	}
+
+	r = newJSONRequest(`{}`)
+	r.Header.Set("Content-Type", "application/vnd.vadilatorgolang.v2+json; charset=utf-8")
+	if err := JSON(r, &jsonTarget{}); err != nil {
+		t.Errorf("vendor +json: err = %v", err)
+	}
+
+	r = newJSONRequest(`{"name":"` + strings.Repeat("a", 64) + `"}`)
+	r.ContentLength = -1 // không biết trước kích thước, phải chặn khi đọc
+	if err := JSONLimit(r, &jsonTarget{}, 16); !errors.Is(err, ErrBodyTooLarge) {
+		t.Errorf("body lớn: err = %v", err)
+	}
+
+	// JSON không tự đặt giới hạn, chỉ theo giới hạn của BodyLimit
+	big := `{"name":"` + strings.Repeat("a", 2<<20) + `"}`
+	if err := JSON(newJSONRequest(big), &jsonTarget{}); err != nil {
+		t.Errorf("body 2 MiB không có BodyLimit: err = %v", err)
+	}
+	r = newJSONRequest(big)
+	r.Body = http.MaxBytesReader(httptest.NewRecorder(), r.Body, 1<<20)
+	if err := JSON(r, &jsonTarget{}); !errors.Is(err, ErrBodyTooLarge) {
+		t.Errorf("body vượt BodyLimit: err = %v", err)
+	}
+}
+
+func TestKeyOffset(t *testing.T) {
+	tests := []struct {
+		data string
+		name string
+		want int64
+		ok   bool
+	}{
+		{`{"a":1}`, "a", 2, true},
+		{`{"a":"b","b":2}`, "b", 10, true},
+		{`{"x":{"b":1}}`, "b", 7, true},
+		{`[{"a":1},{"b":[1,{"c":3}]}]`, "c", 19, true},
+		{`{"a":1}`, "b", 0, false},
+		{`{"a":`, "b", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := keyOffset([]byte(tt.data), tt.name)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("keyOffset(%s, %q) = %d, %v; muốn %d, %v", tt.data, tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+}
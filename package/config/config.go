@@ -0,0 +1,403 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// Config là cấu hình hiệu lực của toàn bộ ứng dụng.
+// Thứ tự ưu tiên khi nạp: mặc định < file (YAML/JSON) < biến môi trường < flag.
+type Config struct {
+	App      AppConfig      `yaml:"app"`
+	Server   ServerConfig   `yaml:"server"`
+	Database DatabaseConfig `yaml:"database"`
+	Log      LogConfig      `yaml:"log"`
+	Admin    AdminConfig    `yaml:"admin"`
+	Metrics  MetricsConfig  `yaml:"metrics"`
+	Tracing  TracingConfig  `yaml:"tracing"`
+	Health   HealthConfig   `yaml:"health"`
+}
+
+// AppConfig chứa thông tin chung của ứng dụng
+type AppConfig struct {
+	Env string `yaml:"env" env:"APP_ENV" flag:"env" usage:"môi trường chạy (development|staging|production)" validate:"required,oneof=development staging production"`
+}
+
+// ServerConfig chứa cấu hình HTTP server
+type ServerConfig struct {
+	Host string `yaml:"host" env:"SERVER_HOST" flag:"host" usage:"địa chỉ lắng nghe của HTTP server"`
+	Port int    `yaml:"port" env:"SERVER_PORT" flag:"port" usage:"cổng của HTTP server" validate:"required,gte=1,lte=65535"`
+
+	ReadTimeout       time.Duration `yaml:"read_timeout" env:"SERVER_READ_TIMEOUT" flag:"read-timeout" usage:"thời gian tối đa đọc toàn bộ request" validate:"gte=0"`
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout" env:"SERVER_READ_HEADER_TIMEOUT" flag:"read-header-timeout" usage:"thời gian tối đa đọc header của request" validate:"gte=0"`
+	WriteTimeout      time.Duration `yaml:"write_timeout" env:"SERVER_WRITE_TIMEOUT" flag:"write-timeout" usage:"thời gian tối đa ghi response" validate:"gte=0"`
+	IdleTimeout       time.Duration `yaml:"idle_timeout" env:"SERVER_IDLE_TIMEOUT" flag:"idle-timeout" usage:"thời gian giữ kết nối keep-alive rảnh" validate:"gte=0"`
+	MaxHeaderBytes    int           `yaml:"max_header_bytes" env:"SERVER_MAX_HEADER_BYTES" flag:"max-header-bytes" usage:"kích thước header tối đa (byte)" validate:"gte=0"`
+	// ShutdownTimeout là thời gian chờ các request đang xử lý khi tắt server
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env:"SERVER_SHUTDOWN_TIMEOUT" flag:"shutdown-timeout" usage:"thời gian chờ request đang xử lý khi tắt" validate:"gte=0"`
+	// TrustedProxies là các IP/CIDR được tin cậy khi đọc X-Forwarded-For
+	TrustedProxies []string `yaml:"trusted_proxies" env:"SERVER_TRUSTED_PROXIES" flag:"trusted-proxies" usage:"IP/CIDR của proxy tin cậy, ngăn cách bởi dấu phẩy"`
+	// RequestTimeout là hạn xử lý gắn vào context của mỗi request
+	RequestTimeout time.Duration `yaml:"request_timeout" env:"SERVER_REQUEST_TIMEOUT" flag:"request-timeout" usage:"hạn xử lý mỗi request (0 = không giới hạn)" validate:"gte=0"`
+	MaxBodyBytes   int64         `yaml:"max_body_bytes" env:"SERVER_MAX_BODY_BYTES" flag:"max-body-bytes" usage:"kích thước body tối đa của request (byte, 0 = không giới hạn)" validate:"gte=0"`
+	CORS           CORSConfig    `yaml:"cors"`
+	// CrashOnPanic làm tiến trình thoát ngay khi handler panic (chỉ nên bật khi dev)
+	CrashOnPanic bool              `yaml:"crash_on_panic" env:"SERVER_CRASH_ON_PANIC" flag:"crash-on-panic" usage:"thoát tiến trình khi handler panic (dev)"`
+	RateLimit    RateLimitConfig   `yaml:"rate_limit"`
+	Compression  CompressionConfig `yaml:"compression"`
+	API          APIConfig         `yaml:"api"`
+	Auth         AuthConfig        `yaml:"auth"`
+}
+
+// AuthConfig xác thực client của API (/user, /audit, /api/...) bằng API key.
+// Mỗi phần tử Keys có dạng "actor:key"; actor là danh tính ghi vào audit log.
+type AuthConfig struct {
+	Header string   `yaml:"header" env:"AUTH_HEADER" flag:"auth-header" usage:"header chứa API key" validate:"required"`
+	Keys   []string `yaml:"keys" env:"AUTH_KEYS" flag:"auth-keys" usage:"API key dạng actor:key, ngăn cách bởi dấu phẩy" secret:"true"`
+	// Required = false thì request không có API key vẫn đi tiếp, audit log ghi actor là anonymous
+	Required bool `yaml:"required" env:"AUTH_REQUIRED" flag:"auth-required" usage:"bắt buộc API key cho các route của API"`
+}
+
+// APIConfig điều khiển phiên bản API. Ngày theo dạng 2006-01-02 (UTC).
+type APIConfig struct {
+	// DefaultVersion là phiên bản của /api/users... khi Accept không chỉ định
+	DefaultVersion int `yaml:"default_version" env:"API_DEFAULT_VERSION" flag:"api-default-version" usage:"phiên bản API khi Accept không chỉ định (1|2)" validate:"oneof=1 2"`
+	// LegacyDeprecatedAt và LegacySunset gắn vào header Deprecation/Sunset của route cũ /user
+	LegacyDeprecatedAt string `yaml:"legacy_deprecated_at" env:"API_LEGACY_DEPRECATED_AT" flag:"api-legacy-deprecated-at" usage:"ngày route cũ /user bị deprecate (YYYY-MM-DD)" validate:"required,datetime=2006-01-02"`
+	LegacySunset       string `yaml:"legacy_sunset" env:"API_LEGACY_SUNSET" flag:"api-legacy-sunset" usage:"ngày dự kiến gỡ route cũ /user (YYYY-MM-DD, rỗng = chưa định)" validate:"omitempty,datetime=2006-01-02"`
+}
+
+// DeprecatedAt trả về LegacyDeprecatedAt dạng time.Time
+func (a APIConfig) DeprecatedAt() time.Time { return parseDate(a.LegacyDeprecatedAt) }
+
+// Sunset trả về LegacySunset dạng time.Time, zero nếu chưa đặt
+func (a APIConfig) Sunset() time.Time { return parseDate(a.LegacySunset) }
+
+// parseDate đọc ngày đã qua validate, lỗi trả về zero
+func parseDate(s string) time.Time {
+	t, _ := time.Parse(time.DateOnly, s)
+	return t
+}
+
+// CompressionConfig điều khiển nén response (gzip/deflate). Level theo
+// compress/flate: -1 = mặc định, 1 (nhanh) .. 9 (nhỏ nhất).
+type CompressionConfig struct {
+	Enabled bool `yaml:"enabled" env:"COMPRESSION_ENABLED" flag:"compression" usage:"nén response theo Accept-Encoding"`
+	Level   int  `yaml:"level" env:"COMPRESSION_LEVEL" flag:"compression-level" usage:"mức nén (-1 = mặc định, 1..9)" validate:"gte=-1,lte=9"`
+	// MinSize là kích thước body tối thiểu để nén; body nhỏ hơn gửi nguyên
+	MinSize int `yaml:"min_size" env:"COMPRESSION_MIN_SIZE" flag:"compression-min-size" usage:"kích thước body tối thiểu để nén (byte)" validate:"gte=0"`
+}
+
+// RateLimitConfig giới hạn số request của mỗi client theo token bucket:
+// Rate request mỗi Period, dồn tối đa Burst (0 = bằng Rate). Rate = 0 là không giới hạn.
+type RateLimitConfig struct {
+	Enabled bool `yaml:"enabled" env:"RATE_LIMIT_ENABLED" flag:"rate-limit" usage:"bật giới hạn tần suất request"`
+	// Key chọn cách nhận diện client: ip hoặc api_key (API key hợp lệ theo
+	// server.auth); thiếu API key hoặc key sai thì dùng IP
+	Key    string        `yaml:"key" env:"RATE_LIMIT_KEY" flag:"rate-limit-key" usage:"nhận diện client theo ip|api_key" validate:"oneof=ip api_key"`
+	Rate   int           `yaml:"rate" env:"RATE_LIMIT_RATE" flag:"rate-limit-rate" usage:"số request mỗi period" validate:"gte=0"`
+	Period time.Duration `yaml:"period" env:"RATE_LIMIT_PERIOD" flag:"rate-limit-period" usage:"chu kỳ của rate" validate:"gte=0"`
+	Burst  int           `yaml:"burst" env:"RATE_LIMIT_BURST" flag:"rate-limit-burst" usage:"số request dồn tối đa (0 = bằng rate)" validate:"gte=0"`
+	// Routes ghi đè giới hạn cho từng route, chỉ đặt được trong file cấu hình
+	Routes []RouteRateLimit `yaml:"routes" validate:"dive"`
+}
+
+// RouteRateLimit là giới hạn riêng của một route, ví dụ "POST /users". Route
+// không kèm phiên bản và áp chung một bucket cho mọi alias (/api/v1/users,
+// /api/v2/users, /api/users, /user). Key rỗng thì dùng Key chung.
+type RouteRateLimit struct {
+	Route  string        `yaml:"route" validate:"required"`
+	Key    string        `yaml:"key" validate:"omitempty,oneof=ip api_key"`
+	Rate   int           `yaml:"rate" validate:"gte=0"`
+	Period time.Duration `yaml:"period" validate:"gte=0"`
+	Burst  int           `yaml:"burst" validate:"gte=0"`
+}
+
+// CORSConfig điều khiển Cross-Origin Resource Sharing. AllowedOrigins rỗng = tắt CORS.
+type CORSConfig struct {
+	AllowedOrigins   []string      `yaml:"allowed_origins" env:"SERVER_CORS_ALLOWED_ORIGINS" flag:"cors-allowed-origins" usage:"origin được phép, ngăn cách bởi dấu phẩy (* = mọi origin)"`
+	AllowedMethods   []string      `yaml:"allowed_methods" env:"SERVER_CORS_ALLOWED_METHODS" flag:"cors-allowed-methods" usage:"method được phép, ngăn cách bởi dấu phẩy"`
+	AllowedHeaders   []string      `yaml:"allowed_headers" env:"SERVER_CORS_ALLOWED_HEADERS" flag:"cors-allowed-headers" usage:"header request được phép, ngăn cách bởi dấu phẩy"`
+	ExposedHeaders   []string      `yaml:"exposed_headers" env:"SERVER_CORS_EXPOSED_HEADERS" flag:"cors-exposed-headers" usage:"header response cho phép trình duyệt đọc, ngăn cách bởi dấu phẩy"`
+	AllowCredentials bool          `yaml:"allow_credentials" env:"SERVER_CORS_ALLOW_CREDENTIALS" flag:"cors-allow-credentials" usage:"cho phép gửi cookie/Authorization"`
+	MaxAge           time.Duration `yaml:"max_age" env:"SERVER_CORS_MAX_AGE" flag:"cors-max-age" usage:"thời gian trình duyệt cache kết quả preflight" validate:"gte=0"`
+}
+
+// Addr trả về địa chỉ dạng host:port cho http.Server
+func (s ServerConfig) Addr() string {
+	return net.JoinHostPort(s.Host, strconv.Itoa(s.Port))
+}
+
+// DatabaseConfig chứa thông tin kết nối MySQL
+type DatabaseConfig struct {
+	Host            string        `yaml:"host" env:"DB_HOST" flag:"db-host" usage:"host MySQL" validate:"required"`
+	Port            int           `yaml:"port" env:"DB_PORT" flag:"db-port" usage:"cổng MySQL" validate:"required,gte=1,lte=65535"`
+	User            string        `yaml:"user" env:"DB_USER" flag:"db-user" usage:"user MySQL" validate:"required"`
+	Password        string        `yaml:"password" env:"DB_PASSWORD" flag:"db-password" usage:"mật khẩu MySQL" secret:"true"`
+	Name            string        `yaml:"name" env:"DB_NAME" flag:"db-name" usage:"tên database" validate:"required"`
+	MaxOpenConns    int           `yaml:"max_open_conns" env:"DB_MAX_OPEN_CONNS" flag:"db-max-open-conns" usage:"số kết nối mở tối đa" validate:"gte=0"`
+	MaxIdleConns    int           `yaml:"max_idle_conns" env:"DB_MAX_IDLE_CONNS" flag:"db-max-idle-conns" usage:"số kết nối rảnh tối đa" validate:"gte=0"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" env:"DB_CONN_MAX_LIFETIME" flag:"db-conn-max-lifetime" usage:"thời gian sống tối đa của một kết nối" validate:"gte=0"`
+	// RequireMigrations = true thì server từ chối khởi động khi còn migration chưa chạy
+	RequireMigrations bool               `yaml:"require_migrations" env:"DB_REQUIRE_MIGRATIONS" flag:"require-migrations" usage:"từ chối khởi động khi còn migration chưa chạy"`
+	Timeouts          TimeoutConfig      `yaml:"timeouts"`
+	ConnectRetry      ConnectRetryConfig `yaml:"connect_retry"`
+}
+
+// ConnectRetryConfig điều khiển việc thử kết nối lại database lúc khởi động.
+// Thời gian chờ giữa các lần thử tăng gấp đôi từ InitialBackoff tới MaxBackoff.
+type ConnectRetryConfig struct {
+	// Timeout là tổng thời gian thử; 0 = chỉ thử một lần
+	Timeout        time.Duration `yaml:"timeout" env:"DB_CONNECT_TIMEOUT" flag:"db-connect-timeout" usage:"tổng thời gian thử kết nối database lúc khởi động (0 = thử một lần)" validate:"gte=0"`
+	InitialBackoff time.Duration `yaml:"initial_backoff" env:"DB_CONNECT_INITIAL_BACKOFF" flag:"db-connect-initial-backoff" usage:"thời gian chờ trước lần thử lại đầu tiên" validate:"gt=0"`
+	MaxBackoff     time.Duration `yaml:"max_backoff" env:"DB_CONNECT_MAX_BACKOFF" flag:"db-connect-max-backoff" usage:"thời gian chờ tối đa giữa hai lần thử" validate:"gtefield=InitialBackoff"`
+	// AttemptTimeout giới hạn mỗi lần thử, để một lần ping treo (firewall bỏ gói) không ăn hết Timeout
+	AttemptTimeout time.Duration `yaml:"attempt_timeout" env:"DB_CONNECT_ATTEMPT_TIMEOUT" flag:"db-connect-attempt-timeout" usage:"thời gian tối đa của mỗi lần thử kết nối database" validate:"gt=0"`
+}
+
+// HealthConfig điều khiển các endpoint /livez, /readyz, /healthz
+type HealthConfig struct {
+	// CacheTTL là thời gian dùng lại kết quả của mỗi check
+	CacheTTL time.Duration `yaml:"cache_ttl" env:"HEALTH_CACHE_TTL" flag:"health-cache-ttl" usage:"thời gian dùng lại kết quả health check" validate:"gte=0"`
+	Timeout  time.Duration `yaml:"timeout" env:"HEALTH_TIMEOUT" flag:"health-timeout" usage:"thời gian tối đa của mỗi health check" validate:"gt=0"`
+	// DiskMinFreeMB là dung lượng trống tối thiểu của ổ chứa log.dir
+	DiskMinFreeMB uint64 `yaml:"disk_min_free_mb" env:"HEALTH_DISK_MIN_FREE_MB" flag:"health-disk-min-free-mb" usage:"dung lượng trống tối thiểu (MB) của ổ chứa thư mục log"`
+	// DrainDelay là thời gian /readyz báo 503 trước khi http server ngừng nhận
+	// kết nối, đủ để load balancer thấy và bỏ instance ra. Tính vào ShutdownTimeout.
+	DrainDelay time.Duration `yaml:"drain_delay" env:"HEALTH_DRAIN_DELAY" flag:"health-drain-delay" usage:"thời gian báo 503 ở /readyz trước khi tắt http server" validate:"gte=0"`
+}
+
+// TimeoutConfig giới hạn thời gian cho từng loại thao tác với database.
+// Giá trị 0 nghĩa là dùng Default.
+type TimeoutConfig struct {
+	Default time.Duration `yaml:"default" env:"DB_TIMEOUT_DEFAULT" flag:"db-timeout" usage:"timeout mặc định cho mỗi truy vấn" validate:"gte=0"`
+	Create  time.Duration `yaml:"create" env:"DB_TIMEOUT_CREATE" flag:"db-timeout-create" usage:"timeout cho thao tác tạo" validate:"gte=0"`
+	Get     time.Duration `yaml:"get" env:"DB_TIMEOUT_GET" flag:"db-timeout-get" usage:"timeout cho thao tác đọc một bản ghi" validate:"gte=0"`
+	List    time.Duration `yaml:"list" env:"DB_TIMEOUT_LIST" flag:"db-timeout-list" usage:"timeout cho thao tác lấy danh sách" validate:"gte=0"`
+	Update  time.Duration `yaml:"update" env:"DB_TIMEOUT_UPDATE" flag:"db-timeout-update" usage:"timeout cho thao tác cập nhật" validate:"gte=0"`
+	Delete  time.Duration `yaml:"delete" env:"DB_TIMEOUT_DELETE" flag:"db-timeout-delete" usage:"timeout cho thao tác xóa" validate:"gte=0"`
+}
+
+// DSN dựng chuỗi kết nối cho driver go-sql-driver/mysql
+func (d DatabaseConfig) DSN() string {
+	c := mysql.NewConfig()
+	c.User = d.User
+	c.Passwd = d.Password
+	c.Net = "tcp"
+	c.Addr = net.JoinHostPort(d.Host, strconv.Itoa(d.Port))
+	c.DBName = d.Name
+	c.ParseTime = true
+	// RowsAffected của UPDATE trả về số dòng khớp điều kiện, để update
+	// không đổi dữ liệu không bị hiểu nhầm là "không tìm thấy"
+	c.ClientFoundRows = true
+	return c.FormatDSN()
+}
+
+// LogConfig chứa cấu hình ghi log
+type LogConfig struct {
+	Dir     string          `yaml:"dir" env:"LOG_DIR" flag:"log-dir" usage:"thư mục chứa file log" validate:"required"`
+	Level   string          `yaml:"level" env:"LOG_LEVEL" flag:"log-level" usage:"mức log tối thiểu (trace|debug|info|warn|error)" validate:"oneof=trace debug info warn error"`
+	Format  string          `yaml:"format" env:"LOG_FORMAT" flag:"log-format" usage:"định dạng log (text|json)" validate:"oneof=text json"`
+	Console bool            `yaml:"console" env:"LOG_CONSOLE" flag:"log-console" usage:"ghi log kèm ra stdout/stderr"`
+	Rotate  LogRotateConfig `yaml:"rotate"`
+	Redact  LogRedactConfig `yaml:"redact"`
+	Access  AccessLogConfig `yaml:"access"`
+}
+
+// AccessLogConfig điều khiển access log của HTTP server (một dòng mỗi request)
+type AccessLogConfig struct {
+	Enabled bool   `yaml:"enabled" env:"LOG_ACCESS_ENABLED" flag:"access-log" usage:"bật access log"`
+	Format  string `yaml:"format" env:"LOG_ACCESS_FORMAT" flag:"access-log-format" usage:"định dạng access log (common|combined|json)" validate:"oneof=common combined json"`
+	// File nằm trong thư mục log, được xoay vòng như các file log khác
+	File string `yaml:"file" env:"LOG_ACCESS_FILE" flag:"access-log-file" usage:"tên file access log trong thư mục log" validate:"required"`
+}
+
+// LogRedactConfig chứa các mẫu tên trường (glob, không phân biệt hoa thường)
+// cần che khi ghi log. Tag `log:"redact"`/`log:"mask"` trên struct được ưu tiên.
+type LogRedactConfig struct {
+	RedactFields  []string `yaml:"redact_fields" env:"LOG_REDACT_FIELDS" flag:"log-redact-fields" usage:"mẫu tên trường bị bỏ hẳn khỏi log, ngăn cách bởi dấu phẩy"`
+	MaskFields    []string `yaml:"mask_fields" env:"LOG_MASK_FIELDS" flag:"log-mask-fields" usage:"mẫu tên trường bị che một phần, ngăn cách bởi dấu phẩy"`
+	ScrubMessages bool     `yaml:"scrub_messages" env:"LOG_SCRUB_MESSAGES" flag:"log-scrub-messages" usage:"che email xuất hiện trong nội dung message"`
+}
+
+// LogRotateConfig điều khiển việc xoay vòng, nén và dọn file log cũ.
+// Giá trị 0 tắt tiêu chí tương ứng.
+type LogRotateConfig struct {
+	MaxSizeMB  int           `yaml:"max_size_mb" env:"LOG_ROTATE_MAX_SIZE_MB" flag:"log-rotate-max-size" usage:"xoay vòng khi file vượt quá N MB" validate:"gte=0"`
+	Interval   time.Duration `yaml:"interval" env:"LOG_ROTATE_INTERVAL" flag:"log-rotate-interval" usage:"xoay vòng định kỳ (ví dụ 24h)" validate:"gte=0"`
+	Compress   bool          `yaml:"compress" env:"LOG_ROTATE_COMPRESS" flag:"log-rotate-compress" usage:"nén gzip file đã xoay vòng"`
+	MaxAge     time.Duration `yaml:"max_age" env:"LOG_ROTATE_MAX_AGE" flag:"log-rotate-max-age" usage:"xóa file cũ hơn khoảng thời gian này" validate:"gte=0"`
+	MaxBackups int           `yaml:"max_backups" env:"LOG_ROTATE_MAX_BACKUPS" flag:"log-rotate-max-backups" usage:"số file cũ tối đa giữ lại cho mỗi mức" validate:"gte=0"`
+}
+
+// AdminConfig chứa cấu hình của server quản trị (pprof, expvar, build info,
+// cấu hình hiệu lực, thống kê runtime/DB, mức log). Server này lắng nghe ở
+// cổng riêng, mặc định chỉ trên localhost, không bao giờ trên cổng API.
+type AdminConfig struct {
+	Enabled bool   `yaml:"enabled" env:"ADMIN_ENABLED" flag:"admin" usage:"bật server quản trị"`
+	Host    string `yaml:"host" env:"ADMIN_HOST" flag:"admin-host" usage:"địa chỉ lắng nghe của server quản trị"`
+	Port    int    `yaml:"port" env:"ADMIN_PORT" flag:"admin-port" usage:"cổng của server quản trị" validate:"gte=1,lte=65535"`
+	// Token dùng cho header "Authorization: Bearer <token>"; rỗng = không xác thực,
+	// chỉ được phép khi Host là địa chỉ loopback
+	Token string `yaml:"token" env:"ADMIN_TOKEN" flag:"admin-token" usage:"token xác thực server quản trị (bắt buộc nếu không lắng nghe trên localhost)" secret:"true"`
+}
+
+// Addr trả về địa chỉ dạng host:port của server quản trị
+func (a AdminConfig) Addr() string {
+	return net.JoinHostPort(a.Host, strconv.Itoa(a.Port))
+}
+
+// Loopback báo server quản trị chỉ lắng nghe trên localhost
+func (a AdminConfig) Loopback() bool {
+	if a.Host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(a.Host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// MetricsConfig điều khiển endpoint metrics định dạng Prometheus
+type MetricsConfig struct {
+	Enabled bool   `yaml:"enabled" env:"METRICS_ENABLED" flag:"metrics" usage:"bật endpoint metrics"`
+	Path    string `yaml:"path" env:"METRICS_PATH" flag:"metrics-path" usage:"đường dẫn của endpoint metrics" validate:"required,startswith=/"`
+}
+
+// TracingConfig điều khiển tracing theo W3C Trace Context
+type TracingConfig struct {
+	Enabled     bool   `yaml:"enabled" env:"TRACING_ENABLED" flag:"tracing" usage:"bật tracing"`
+	ServiceName string `yaml:"service_name" env:"TRACING_SERVICE_NAME" flag:"tracing-service-name" usage:"tên service gắn vào span" validate:"required"`
+	// Exporter là nơi gửi span: stdout, file (File, tương đối theo log.dir) hoặc otlp (Endpoint)
+	Exporter string        `yaml:"exporter" env:"TRACING_EXPORTER" flag:"tracing-exporter" usage:"nơi gửi span: stdout|file|otlp" validate:"oneof=stdout file otlp"`
+	File     string        `yaml:"file" env:"TRACING_FILE" flag:"tracing-file" usage:"file ghi span (exporter file)"`
+	Endpoint string        `yaml:"endpoint" env:"TRACING_ENDPOINT" flag:"tracing-endpoint" usage:"URL OTLP/HTTP của collector (exporter otlp)" validate:"omitempty,url"`
+	Headers  []string      `yaml:"headers" env:"TRACING_HEADERS" flag:"tracing-headers" usage:"header gửi kèm tới collector, dạng Tên=giá trị, ngăn cách bởi dấu phẩy" secret:"true"`
+	Timeout  time.Duration `yaml:"timeout" env:"TRACING_TIMEOUT" flag:"tracing-timeout" usage:"thời gian chờ tối đa mỗi lần gửi tới collector" validate:"gte=0"`
+	// SampleRatio là tỉ lệ trace mới được ghi lại; request mang traceparent theo quyết định của client
+	SampleRatio   float64       `yaml:"sample_ratio" env:"TRACING_SAMPLE_RATIO" flag:"tracing-sample-ratio" usage:"tỉ lệ sample trace mới (0..1)" validate:"gte=0,lte=1"`
+	BatchSize     int           `yaml:"batch_size" env:"TRACING_BATCH_SIZE" flag:"tracing-batch-size" usage:"số span tối đa mỗi lần export" validate:"gte=1"`
+	FlushInterval time.Duration `yaml:"flush_interval" env:"TRACING_FLUSH_INTERVAL" flag:"tracing-flush-interval" usage:"chu kỳ export span đang chờ" validate:"gt=0"`
+}
+
+// Default trả về cấu hình mặc định, đủ để chạy trên máy dev
+func Default() Config {
+	return Config{
+		App: AppConfig{
+			Env: "development",
+		},
+		Server: ServerConfig{
+			Port:              8080,
+			ReadTimeout:       15 * time.Second,
+			ReadHeaderTimeout: 5 * time.Second,
+			WriteTimeout:      30 * time.Second,
+			IdleTimeout:       60 * time.Second,
+			MaxHeaderBytes:    1 << 20,
+			ShutdownTimeout:   20 * time.Second,
+			RequestTimeout:    30 * time.Second,
+			MaxBodyBytes:      1 << 20,
+			CORS: CORSConfig{
+				AllowedMethods: []string{"GET", "POST", "PUT", "DELETE"},
+				AllowedHeaders: []string{"Content-Type", "Authorization", "X-Request-ID", "X-API-Key"},
+				ExposedHeaders: []string{"X-Request-ID"},
+				MaxAge:         10 * time.Minute,
+			},
+			RateLimit: RateLimitConfig{
+				Key:    "ip",
+				Rate:   100,
+				Period: time.Minute,
+				Burst:  20,
+			},
+			Compression: CompressionConfig{
+				Enabled: true,
+				Level:   -1,
+				MinSize: 1024,
+			},
+			Auth: AuthConfig{
+				Header: "X-API-Key",
+			},
+			API: APIConfig{
+				DefaultVersion:     1,
+				LegacyDeprecatedAt: "2026-10-18",
+				LegacySunset:       "2027-04-18",
+			},
+		},
+		Database: DatabaseConfig{
+			Host:            "127.0.0.1",
+			Port:            3306,
+			User:            "root",
+			Name:            "user_manage",
+			MaxOpenConns:    25,
+			MaxIdleConns:    25,
+			ConnMaxLifetime: 5 * time.Minute,
+			Timeouts: TimeoutConfig{
+				Default: 5 * time.Second,
+				List:    10 * time.Second,
+			},
+			ConnectRetry: ConnectRetryConfig{
+				Timeout:        time.Minute,
+				InitialBackoff: 500 * time.Millisecond,
+				MaxBackoff:     10 * time.Second,
+				AttemptTimeout: 5 * time.Second,
+			},
+		},
+		Log: LogConfig{
+			Dir:     "log",
+			Level:   "trace",
+			Format:  "text",
+			Console: true,
+			Rotate: LogRotateConfig{
+				MaxSizeMB:  100,
+				Interval:   24 * time.Hour,
+				Compress:   true,
+				MaxAge:     7 * 24 * time.Hour,
+				MaxBackups: 10,
+			},
+			Redact: LogRedactConfig{
+				RedactFields:  []string{"*password*", "*secret*", "*token*", "authorization", "cookie"},
+				MaskFields:    []string{"*email*", "*phone*"},
+				ScrubMessages: true,
+			},
+			Access: AccessLogConfig{
+				Enabled: true,
+				Format:  "combined",
+				File:    "access.log",
+			},
+		},
+		Health: HealthConfig{
+			CacheTTL:      2 * time.Second,
+			Timeout:       2 * time.Second,
+			DiskMinFreeMB: 100,
+			DrainDelay:    5 * time.Second,
+		},
+		Admin: AdminConfig{
+			Host: "127.0.0.1",
+			Port: 6060,
+		},
+		Metrics: MetricsConfig{
+			Enabled: true,
+			Path:    "/metrics",
+		},
+		Tracing: TracingConfig{
+			ServiceName:   "vadilatorgolang",
+			Exporter:      "stdout",
+			File:          "traces.json",
+			Endpoint:      "http://localhost:4318/v1/traces",
+			Timeout:       10 * time.Second,
+			SampleRatio:   1,
+			BatchSize:     512,
+			FlushInterval: 5 * time.Second,
+		},
+	}
+}
+
+// String in cấu hình dạng YAML với các trường bí mật đã được che
+func (c Config) String() string {
+	out, err := marshalMasked(c)
+	if err != nil {
+		return fmt.Sprintf("config: %v", err)
+	}
+	return string(out)
+}
@@ -0,0 +1,268 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	customValidator "vadilatorgolang/package/validator"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvConfigFile là biến môi trường chỉ tới file cấu hình khi không truyền flag -config
+const EnvConfigFile = "CONFIG_FILE"
+
+// Loader nạp cấu hình theo từng lớp. FlagSet được để public để nơi gọi
+// (ví dụ cmd/main.go) có thể đăng ký thêm flag riêng trước khi gọi Load.
+type Loader struct {
+	FlagSet    *flag.FlagSet
+	configPath string
+	flags      map[string]*flagValue
+}
+
+// NewLoader tạo Loader và đăng ký flag cho mọi trường có tag `flag`
+func NewLoader(name string) *Loader {
+	l := &Loader{
+		FlagSet: flag.NewFlagSet(name, flag.ContinueOnError),
+		flags:   make(map[string]*flagValue),
+	}
+	l.FlagSet.StringVar(&l.configPath, "config", "", "đường dẫn file cấu hình YAML/JSON (hoặc biến "+EnvConfigFile+")")
+
+	def := Default()
+	walkFields(reflect.ValueOf(&def).Elem(), func(f reflect.StructField, v reflect.Value) error {
+		name := f.Tag.Get("flag")
+		if name == "" {
+			return nil
+		}
+		fv := &flagValue{def: formatValue(v), isBool: v.Kind() == reflect.Bool}
+		if f.Tag.Get("secret") == "true" {
+			fv.def = ""
+		}
+		l.flags[name] = fv
+		l.FlagSet.Var(fv, name, f.Tag.Get("usage"))
+		return nil
+	})
+	return l
+}
+
+// Load là lối tắt cho NewLoader(os.Args[0]).Load(args)
+func Load(args []string) (*Config, error) {
+	return NewLoader(os.Args[0]).Load(args)
+}
+
+// Load nạp cấu hình: mặc định → file → biến môi trường → flag, sau đó validate
+func (l *Loader) Load(args []string) (*Config, error) {
+	if err := l.FlagSet.Parse(args); err != nil {
+		return nil, err
+	}
+
+	cfg := Default()
+
+	path := l.configPath
+	if path == "" {
+		path = os.Getenv(EnvConfigFile)
+	}
+	if path != "" {
+		if err := loadFile(path, &cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := loadEnv(&cfg); err != nil {
+		return nil, err
+	}
+
+	if err := l.applyFlags(&cfg); err != nil {
+		return nil, err
+	}
+
+	if err := customValidator.ValidateStruct(cfg); err != nil {
+		return nil, fmt.Errorf("config không hợp lệ: %w", err)
+	}
+	return &cfg, nil
+}
+
+// loadFile đọc file YAML hoặc JSON. JSON là tập con của YAML nên dùng chung
+// một decoder; key lạ bị từ chối để tránh gõ sai tên mà không biết.
+func loadFile(path string, cfg *Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("không thể đọc file cấu hình %s: %w", path, err)
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("file cấu hình %s không hợp lệ: %w", path, err)
+	}
+	return nil
+}
+
+// loadEnv áp dụng biến môi trường theo tag `env`. Với mỗi biến NAME,
+// biến NAME_FILE (nếu có) trỏ tới file chứa giá trị — dùng cho secret.
+func loadEnv(cfg *Config) error {
+	return walkFields(reflect.ValueOf(cfg).Elem(), func(f reflect.StructField, v reflect.Value) error {
+		name := f.Tag.Get("env")
+		if name == "" {
+			return nil
+		}
+		raw, ok := os.LookupEnv(name)
+		if file, fok := os.LookupEnv(name + "_FILE"); fok {
+			if ok {
+				return fmt.Errorf("chỉ được đặt một trong %s và %s_FILE", name, name)
+			}
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return fmt.Errorf("không thể đọc %s_FILE: %w", name, err)
+			}
+			raw, ok = strings.TrimRight(string(data), "\r\n"), true
+		}
+		if !ok {
+			return nil
+		}
+		if err := setValue(v, raw); err != nil {
+			return fmt.Errorf("biến môi trường %s: %w", name, err)
+		}
+		return nil
+	})
+}
+
+// applyFlags chỉ áp dụng các flag thực sự được truyền trên dòng lệnh
+func (l *Loader) applyFlags(cfg *Config) error {
+	return walkFields(reflect.ValueOf(cfg).Elem(), func(f reflect.StructField, v reflect.Value) error {
+		fv, ok := l.flags[f.Tag.Get("flag")]
+		if !ok || !fv.set {
+			return nil
+		}
+		if err := setValue(v, fv.raw); err != nil {
+			return fmt.Errorf("flag -%s: %w", f.Tag.Get("flag"), err)
+		}
+		return nil
+	})
+}
+
+// flagValue giữ nguyên chuỗi người dùng truyền vào; việc chuyển kiểu
+// được làm ở applyFlags để dùng chung setValue với biến môi trường.
+type flagValue struct {
+	raw    string
+	def    string
+	set    bool
+	isBool bool
+}
+
+func (f *flagValue) String() string {
+	if f == nil {
+		return ""
+	}
+	if f.set {
+		return f.raw
+	}
+	return f.def
+}
+
+// IsBoolFlag cho phép viết -flag thay cho -flag=true
+func (f *flagValue) IsBoolFlag() bool { return f.isBool }
+
+func (f *flagValue) Set(s string) error {
+	f.raw, f.set = s, true
+	return nil
+}
+
+// walkFields duyệt đệ quy mọi trường lá của struct
+func walkFields(v reflect.Value, fn func(reflect.StructField, reflect.Value) error) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f, fv := t.Field(i), v.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		if fv.Kind() == reflect.Struct {
+			if err := walkFields(fv, fn); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := fn(f, fv); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// setValue chuyển chuỗi sang kiểu của trường và gán vào
+func setValue(v reflect.Value, raw string) error {
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(raw, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(n)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("kiểu %s không được hỗ trợ", v.Type())
+		}
+		var items []string
+		for _, s := range strings.Split(raw, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				items = append(items, s)
+			}
+		}
+		v.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("kiểu %s không được hỗ trợ", v.Type())
+	}
+	return nil
+}
+
+// formatValue là chiều ngược của setValue, dùng để hiển thị giá trị mặc định
+func formatValue(v reflect.Value) string {
+	if v.Type() == durationType {
+		return time.Duration(v.Int()).String()
+	}
+	if v.Kind() == reflect.Slice {
+		parts := make([]string, v.Len())
+		for i := range parts {
+			parts[i] = fmt.Sprint(v.Index(i).Interface())
+		}
+		return strings.Join(parts, ",")
+	}
+	return fmt.Sprint(v.Interface())
+}
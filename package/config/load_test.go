@@ -0,0 +1,190 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// envNames là các biến môi trường test dùng tới; được xóa trước mỗi case để
+// môi trường của máy chạy test không ảnh hưởng kết quả
+var envNames = []string{
+	EnvConfigFile, "SERVER_PORT", "DB_HOST", "LOG_LEVEL",
+	"DB_PASSWORD", "DB_PASSWORD_FILE", "AUTH_KEYS", "AUTH_KEYS_FILE",
+}
+
+func clearEnv(t *testing.T) {
+	t.Helper()
+	for _, name := range envNames {
+		t.Setenv(name, "")
+		os.Unsetenv(name)
+	}
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	const file = "server:\n  port: 9000\ndatabase:\n  host: db.file\nlog:\n  level: info\n"
+	tests := []struct {
+		name  string
+		file  string // nội dung file cấu hình, rỗng = không có file
+		byEnv bool   // chỉ file qua CONFIG_FILE thay vì -config
+		env   map[string]string
+		args  []string
+		port  int
+		host  string
+		level string
+	}{
+		{name: "mặc định", port: 8080, host: "127.0.0.1", level: "trace"},
+		{name: "file ghi đè mặc định", file: file, port: 9000, host: "db.file", level: "info"},
+		{name: "file qua CONFIG_FILE", file: file, byEnv: true, port: 9000, host: "db.file", level: "info"},
+		{name: "env ghi đè file", file: file,
+			env:  map[string]string{"SERVER_PORT": "9100", "LOG_LEVEL": "warn"},
+			port: 9100, host: "db.file", level: "warn"},
+		{name: "flag ghi đè env", file: file,
+			env:  map[string]string{"SERVER_PORT": "9100", "DB_HOST": "db.env"},
+			args: []string{"-port", "9200"},
+			port: 9200, host: "db.env", level: "info"},
+		{name: "flag không có file", args: []string{"-db-host=db.flag", "-log-level", "error"},
+			port: 8080, host: "db.flag", level: "error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+			args := tt.args
+			if tt.file != "" {
+				path := writeFile(t, "config.yaml", tt.file)
+				if tt.byEnv {
+					t.Setenv(EnvConfigFile, path)
+				} else {
+					args = append([]string{"-config", path}, args...)
+				}
+			}
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			cfg, err := NewLoader("test").Load(args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cfg.Server.Port != tt.port || cfg.Database.Host != tt.host || cfg.Log.Level != tt.level {
+				t.Errorf("port=%d host=%s level=%s, muốn port=%d host=%s level=%s",
+					cfg.Server.Port, cfg.Database.Host, cfg.Log.Level, tt.port, tt.host, tt.level)
+			}
+		})
+	}
+}
+
+func TestLoadEnvFile(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("DB_PASSWORD_FILE", writeFile(t, "password", "s3cret\r\n"))
+	t.Setenv("AUTH_KEYS_FILE", writeFile(t, "keys", "alice:k1, bob:k2\n"))
+	cfg, err := NewLoader("test").Load(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Database.Password != "s3cret" {
+		t.Errorf("Password = %q, muốn s3cret (bỏ xuống dòng cuối file)", cfg.Database.Password)
+	}
+	if want := []string{"alice:k1", "bob:k2"}; !reflect.DeepEqual(cfg.Server.Auth.Keys, want) {
+		t.Errorf("Keys = %q, muốn %q", cfg.Server.Auth.Keys, want)
+	}
+
+	t.Setenv("DB_PASSWORD", "khac")
+	if _, err := NewLoader("test").Load(nil); err == nil || !strings.Contains(err.Error(), "DB_PASSWORD_FILE") {
+		t.Errorf("đặt cả DB_PASSWORD và DB_PASSWORD_FILE: err = %v", err)
+	}
+
+	clearEnv(t)
+	t.Setenv("DB_PASSWORD_FILE", filepath.Join(t.TempDir(), "khong-co"))
+	if _, err := NewLoader("test").Load(nil); err == nil {
+		t.Error("DB_PASSWORD_FILE trỏ tới file không tồn tại: muốn lỗi")
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		env  map[string]string
+		args []string
+		want string // phải có trong thông báo lỗi
+	}{
+		{name: "key lạ trong file", file: "server:\n  prot: 9000\n", want: "prot"},
+		{name: "key lạ ở gốc", file: "sever:\n  port: 9000\n", want: "sever"},
+		{name: "sai kiểu trong file", file: "server:\n  port: abc\n", want: "abc"},
+		{name: "env sai kiểu", env: map[string]string{"SERVER_PORT": "abc"}, want: "SERVER_PORT"},
+		{name: "flag sai kiểu", args: []string{"-port", "abc"}, want: "-port"},
+		{name: "vi phạm validate", args: []string{"-log-level", "verbose"}, want: "không hợp lệ"},
+		{name: "flag không tồn tại", args: []string{"-khong-co"}, want: "khong-co"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+			args := tt.args
+			if tt.file != "" {
+				args = append([]string{"-config", writeFile(t, "config.yaml", tt.file)}, args...)
+			}
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			l := NewLoader("test")
+			l.FlagSet.SetOutput(new(strings.Builder))
+			_, err := l.Load(args)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, muốn lỗi chứa %q", err, tt.want)
+			}
+		})
+	}
+
+	// File rỗng chỉ giữ nguyên mặc định
+	clearEnv(t)
+	if _, err := NewLoader("test").Load([]string{"-config", writeFile(t, "config.yaml", "")}); err != nil {
+		t.Errorf("file rỗng: err = %v", err)
+	}
+}
+
+func TestMasked(t *testing.T) {
+	cfg := Default()
+	cfg.Database.Password = "p4ssw0rd"
+	cfg.Server.Auth.Keys = []string{"alice:k1", "bob:k2"}
+	cfg.Admin.Token = ""
+
+	m := cfg.Masked()
+	if m.Database.Password != maskedValue {
+		t.Errorf("Password = %q, muốn bị che", m.Database.Password)
+	}
+	if want := []string{maskedValue, maskedValue}; !reflect.DeepEqual(m.Server.Auth.Keys, want) {
+		t.Errorf("Keys = %q, muốn %q", m.Server.Auth.Keys, want)
+	}
+	if m.Admin.Token != "" {
+		t.Errorf("Token rỗng = %q, muốn giữ rỗng để thấy là chưa đặt", m.Admin.Token)
+	}
+	if m.Database.Host != cfg.Database.Host {
+		t.Errorf("Host = %q, trường thường không được che", m.Database.Host)
+	}
+	// Bản gốc không bị đổi, kể cả slice
+	if cfg.Database.Password != "p4ssw0rd" || cfg.Server.Auth.Keys[0] != "alice:k1" {
+		t.Errorf("Masked làm đổi bản gốc: %q %q", cfg.Database.Password, cfg.Server.Auth.Keys)
+	}
+
+	out := cfg.String()
+	for _, secret := range []string{"p4ssw0rd", "alice:k1", "k2"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("String() lộ %q:\n%s", secret, out)
+		}
+	}
+	if !strings.Contains(out, "password: '"+maskedValue+"'") {
+		t.Errorf("String() thiếu password đã che:\n%s", out)
+	}
+}
@@ -0,0 +1,36 @@
+package config
+
+import (
+	"reflect"
+
+	"gopkg.in/yaml.v3"
+)
+
+// maskedValue thay thế cho mọi giá trị bí mật khi in cấu hình
+const maskedValue = "******"
+
+// Masked trả về bản sao của cấu hình với các trường có tag `secret:"true"` đã bị che
+func (c Config) Masked() Config {
+	walkFields(reflect.ValueOf(&c).Elem(), func(f reflect.StructField, v reflect.Value) error {
+		if f.Tag.Get("secret") != "true" {
+			return nil
+		}
+		switch {
+		case v.Kind() == reflect.String && v.String() != "":
+			v.SetString(maskedValue)
+		case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.String && v.Len() > 0:
+			// Slice dùng chung mảng với bản gốc nên phải tạo slice mới
+			masked := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+			for i := range v.Len() {
+				masked.Index(i).SetString(maskedValue)
+			}
+			v.Set(masked)
+		}
+		return nil
+	})
+	return c
+}
+
+func marshalMasked(c Config) ([]byte, error) {
+	return yaml.Marshal(c.Masked())
+}
@@ -1,21 +1,67 @@
 package database
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
 	"log"
+	"math/rand/v2"
+	"time"
+
+	"vadilatorgolang/package/config"
+
 	_ "github.com/go-sql-driver/mysql"
 )
 
-func ConnectDb() (*sql.DB, error){
-	dsn:="root:11042005@tcp(127.0.0.1:3306)/user_manage?parseTime=true"
-	db, err := sql.Open("mysql", dsn)
+// ConnectDb mở pool kết nối và ping thử một lần trong giới hạn của ctx
+func ConnectDb(ctx context.Context, cfg config.DatabaseConfig) (*sql.DB, error) {
+	db, err := sql.Open("mysql", cfg.DSN())
 	if err != nil {
-		log.Fatalf("Cannot connect to database: %v", err)
+		return nil, fmt.Errorf("cannot connect to database: %w", err)
 	}
-	err = db.Ping()
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+
+	err = db.PingContext(ctx)
 	if err != nil {
-		log.Fatalf("Cannot ping database: %v", err)
+		db.Close()
+		return nil, fmt.Errorf("cannot ping database: %w", err)
 	}
 	log.Println("Connect to database successful")
-	return db,nil
+	return db, nil
+}
+
+// ConnectWithRetry gọi ConnectDb cho tới khi thành công, hết
+// cfg.ConnectRetry.Timeout hoặc ctx bị hủy (ví dụ Ctrl+C). Mỗi lần thử bị giới
+// hạn bởi cfg.ConnectRetry.AttemptTimeout. Thời gian chờ giữa
+// hai lần thử tăng gấp đôi tới MaxBackoff, kèm ngẫu nhiên để nhiều instance
+// khởi động cùng lúc không dồn vào MySQL một lượt. onRetry (có thể nil) được
+// gọi trước mỗi lần chờ, thường dùng để ghi log.
+func ConnectWithRetry(ctx context.Context, cfg config.DatabaseConfig, onRetry func(attempt int, wait time.Duration, err error)) (*sql.DB, error) {
+	deadline := time.Now().Add(cfg.ConnectRetry.Timeout)
+	backoff := cfg.ConnectRetry.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		attemptCtx, cancel := context.WithTimeout(ctx, cfg.ConnectRetry.AttemptTimeout)
+		db, err := ConnectDb(attemptCtx, cfg)
+		cancel()
+		if err == nil {
+			return db, nil
+		}
+
+		// Chờ trong khoảng [backoff/2, backoff]
+		wait := backoff/2 + rand.N(backoff/2+1)
+		if cfg.ConnectRetry.Timeout <= 0 || time.Now().Add(wait).After(deadline) {
+			return nil, fmt.Errorf("sau %d lần thử: %w", attempt, err)
+		}
+		if onRetry != nil {
+			onRetry(attempt, wait, err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%w (lỗi gần nhất: %v)", ctx.Err(), err)
+		case <-time.After(wait):
+		}
+		backoff = min(backoff*2, cfg.ConnectRetry.MaxBackoff)
+	}
 }
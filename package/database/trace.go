@@ -0,0 +1,71 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"vadilatorgolang/package/tracing"
+)
+
+// tracedQuerier mở một span client cho mỗi câu truy vấn. Span chỉ ghi câu
+// SQL có placeholder, không ghi tham số (có thể chứa dữ liệu cá nhân).
+type tracedQuerier struct {
+	q Querier
+}
+
+func (t tracedQuerier) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	ctx, span := startQuery(ctx, query)
+	res, err := t.q.ExecContext(ctx, query, args...)
+	span.Finish(&err)
+	return res, err
+}
+
+// QueryContext: span chỉ tính tới khi có kết quả đầu tiên, không gồm lúc duyệt rows
+func (t tracedQuerier) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	ctx, span := startQuery(ctx, query)
+	rows, err := t.q.QueryContext(ctx, query, args...)
+	span.Finish(&err)
+	return rows, err
+}
+
+func (t tracedQuerier) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	ctx, span := startQuery(ctx, query)
+	row := t.q.QueryRowContext(ctx, query, args...)
+	// sql.ErrNoRows chỉ xuất hiện ở Scan nên không bị tính là lỗi
+	err := row.Err()
+	span.Finish(&err)
+	return row
+}
+
+func startQuery(ctx context.Context, query string) (context.Context, *tracing.Span) {
+	op, table := describeQuery(query)
+	name := op
+	if table != "" {
+		name += " " + table
+	}
+	return tracing.StartClient(ctx, name,
+		tracing.String("db.system", "mysql"),
+		tracing.String("db.operation.name", op),
+		tracing.String("db.collection.name", table),
+		tracing.String("db.query.text", query),
+	)
+}
+
+// describeQuery lấy thao tác và bảng chính của câu SQL đơn giản,
+// ví dụ "insert into nguoi_dung(...)" → INSERT, nguoi_dung
+func describeQuery(query string) (op, table string) {
+	words := strings.Fields(query)
+	if len(words) == 0 {
+		return "QUERY", ""
+	}
+	op = strings.ToUpper(words[0])
+	for i, w := range words[:len(words)-1] {
+		switch strings.ToLower(w) {
+		case "from", "into", "update":
+			table, _, _ = strings.Cut(words[i+1], "(")
+			return op, strings.Trim(table, "`")
+		}
+	}
+	return op, ""
+}
@@ -0,0 +1,67 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"vadilatorgolang/package/tracing"
+)
+
+// Querier là phần chung của *sql.DB và *sql.Tx mà các repo cần
+type Querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// Transactor chạy một đơn vị công việc trong transaction. Transaction được
+// mang theo ctx truyền cho fn, nên mọi repo gọi Conn(ctx, db) bên trong fn
+// đều dùng chung transaction đó.
+type Transactor interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type txKey struct{}
+
+// TxManager là Transactor dựa trên *sql.DB
+type TxManager struct {
+	DB *sql.DB
+}
+
+// NewTxManager tạo TxManager cho db
+func NewTxManager(db *sql.DB) *TxManager {
+	return &TxManager{DB: db}
+}
+
+// WithinTx commit nếu fn trả nil, rollback nếu fn lỗi. Nếu ctx đã mang
+// transaction thì fn chạy luôn trong transaction đó (không lồng nhau).
+func (m *TxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+	ctx, span := tracing.StartClient(ctx, "TRANSACTION", tracing.String("db.system", "mysql"))
+	defer span.Finish(&err)
+
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil && !errors.Is(rbErr, sql.ErrTxDone) {
+			return fmt.Errorf("%w (rollback thất bại: %v)", err, rbErr)
+		}
+		return err
+	}
+	return tx.Commit()
+}
+
+// Conn trả về transaction đang mang trong ctx, nếu không có thì trả về db.
+// Mỗi câu truy vấn qua Querier trả về có một span riêng (xem tracedQuerier).
+func Conn(ctx context.Context, db *sql.DB) Querier {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tracedQuerier{tx}
+	}
+	return tracedQuerier{db}
+}
@@ -0,0 +1,33 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// PingCheck kiểm tra database còn kết nối được
+func PingCheck(db *sql.DB) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		return db.PingContext(ctx)
+	}
+}
+
+// DiskSpaceCheck báo lỗi khi ổ đĩa chứa dir còn trống dưới minFree byte.
+// Trên hệ điều hành không hỗ trợ thì check luôn đạt.
+func DiskSpaceCheck(dir string, minFree uint64) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		free, err := freeSpace(dir)
+		if errors.Is(err, errors.ErrUnsupported) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if free < minFree {
+			return fmt.Errorf("%s còn trống %d MB, dưới mức tối thiểu %d MB", dir, free>>20, minFree>>20)
+		}
+		return nil
+	}
+}
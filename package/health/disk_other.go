@@ -0,0 +1,10 @@
+//go:build !(linux || darwin || freebsd)
+
+package health
+
+import "errors"
+
+// freeSpace chưa hỗ trợ trên hệ điều hành này
+func freeSpace(dir string) (uint64, error) {
+	return 0, errors.ErrUnsupported
+}
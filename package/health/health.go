@@ -0,0 +1,227 @@
+// Package health là registry các kiểm tra sức khỏe (database, ổ đĩa,
+// migration...) và các endpoint /livez, /readyz, /healthz dùng cho probe
+// của Kubernetes/load balancer.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Status là trạng thái của một check hoặc của cả endpoint
+type Status string
+
+const (
+	StatusUp   Status = "up"
+	StatusDown Status = "down"
+)
+
+// Check là một kiểm tra có tên. Func trả về nil khi thành phần hoạt động tốt.
+type Check struct {
+	Name string
+	Func func(ctx context.Context) error
+	// Timeout giới hạn thời gian chạy Func (0 = timeout mặc định của Registry)
+	Timeout time.Duration
+	// Liveness = true thì check được chạy cả ở /livez. Chỉ dùng cho lỗi mà
+	// khởi động lại tiến trình mới sửa được; lỗi của phụ thuộc bên ngoài
+	// (database...) chỉ nên làm request không sẵn sàng.
+	Liveness bool
+	// Optional = true thì lỗi chỉ được báo trong chi tiết, không làm endpoint down
+	Optional bool
+}
+
+// Result là kết quả của một check. Error chỉ có trong báo cáo chi tiết của
+// Details; các endpoint công khai không trả nội dung lỗi (địa chỉ database,
+// đường dẫn...).
+type Result struct {
+	Status     Status    `json:"status"`
+	Error      string    `json:"error,omitempty"`
+	DurationMs float64   `json:"duration_ms"`
+	CheckedAt  time.Time `json:"checked_at"`
+}
+
+// Report là body JSON của các endpoint
+type Report struct {
+	Status Status            `json:"status"`
+	Reason string            `json:"reason,omitempty"`
+	Checks map[string]Result `json:"checks"`
+}
+
+// Registry giữ các check và kết quả gần nhất của chúng. Kết quả được dùng
+// lại trong CacheTTL để probe dày đặc không dồn tải lên database.
+type Registry struct {
+	// Logf nhận lỗi chi tiết khi một check chuyển trạng thái (có thể nil)
+	Logf func(format string, args ...any)
+
+	cacheTTL time.Duration
+	timeout  time.Duration
+
+	mu       sync.RWMutex
+	checks   []*entry
+	draining atomic.Bool
+}
+
+type entry struct {
+	Check
+	mu   sync.Mutex // giữ trong lúc chạy để các request cùng lúc dùng chung một lần chạy
+	last Result
+}
+
+// New tạo Registry; cacheTTL = 0 là luôn chạy lại, timeout là thời gian
+// tối đa mặc định của mỗi check
+func New(cacheTTL, timeout time.Duration) *Registry {
+	return &Registry{cacheTTL: cacheTTL, timeout: timeout}
+}
+
+// Register thêm check; tên trùng sẽ thay check cũ
+func (r *Registry) Register(c Check) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, e := range r.checks {
+		if e.Name == c.Name {
+			r.checks[i] = &entry{Check: c}
+			return
+		}
+	}
+	r.checks = append(r.checks, &entry{Check: c})
+}
+
+// Drain đánh dấu tiến trình đang tắt: /readyz trả 503 để load balancer
+// ngừng gửi request mới, /livez và /healthz không đổi
+func (r *Registry) Drain() { r.draining.Store(true) }
+
+// Run chạy (hoặc lấy từ cache) các check được chọn. Tên trong exclude bị bỏ qua.
+func (r *Registry) Run(ctx context.Context, livenessOnly bool, exclude ...string) Report {
+	r.mu.RLock()
+	var selected []*entry
+	for _, e := range r.checks {
+		if (livenessOnly && !e.Liveness) || contains(exclude, e.Name) {
+			continue
+		}
+		selected = append(selected, e)
+	}
+	r.mu.RUnlock()
+
+	rep := Report{Status: StatusUp, Checks: make(map[string]Result, len(selected))}
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	for _, e := range selected {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res := r.run(ctx, e)
+			mu.Lock()
+			rep.Checks[e.Name] = res
+			if res.Status != StatusUp && !e.Optional {
+				rep.Status = StatusDown
+			}
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return rep
+}
+
+func (r *Registry) run(ctx context.Context, e *entry) Result {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if !e.last.CheckedAt.IsZero() && time.Since(e.last.CheckedAt) < r.cacheTTL {
+		return e.last
+	}
+
+	timeout := e.Timeout
+	if timeout <= 0 {
+		timeout = r.timeout
+	}
+	// Không dùng ctx của request: client ngắt giữa chừng không được làm
+	// kết quả lỗi bị cache cho các probe khác
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := e.Func(ctx)
+	res := Result{
+		Status:     StatusUp,
+		DurationMs: float64(time.Since(start).Microseconds()) / 1000,
+		CheckedAt:  start,
+	}
+	if err != nil {
+		res.Status = StatusDown
+		res.Error = err.Error()
+	}
+	r.logChange(e, res)
+	e.last = res
+	return res
+}
+
+// logChange ghi log khi check đổi trạng thái hoặc đổi lỗi, để probe dày
+// đặc không lặp lại cùng một dòng log
+func (r *Registry) logChange(e *entry, res Result) {
+	if r.Logf == nil || (res.Status == e.last.Status && res.Error == e.last.Error) {
+		return
+	}
+	switch {
+	case res.Status == StatusDown:
+		r.Logf("Health check %s down: %s", e.Name, res.Error)
+	case !e.last.CheckedAt.IsZero():
+		r.Logf("Health check %s up trở lại", e.Name)
+	}
+}
+
+// Livez chỉ chạy các check Liveness: còn phản hồi là còn sống
+func (r *Registry) Livez() http.Handler { return r.handler(true, false, false) }
+
+// Readyz chạy mọi check và trả 503 khi đang tắt (xem Drain)
+func (r *Registry) Readyz() http.Handler { return r.handler(false, true, false) }
+
+// Healthz chạy mọi check, không quan tâm trạng thái tắt
+func (r *Registry) Healthz() http.Handler { return r.handler(false, false, false) }
+
+// Details giống Healthz nhưng kèm nội dung lỗi của từng check; chỉ gắn vào
+// server quản trị
+func (r *Registry) Details() http.Handler { return r.handler(false, false, true) }
+
+// handler trả 200 khi mọi check bắt buộc up, 503 nếu có check bắt buộc down.
+// ?exclude=a,b (hoặc lặp lại) bỏ qua các check theo tên. detailed = false
+// thì bỏ Error khỏi từng kết quả.
+func (r *Registry) handler(livenessOnly, readiness, detailed bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var exclude []string
+		for _, v := range req.URL.Query()["exclude"] {
+			exclude = append(exclude, strings.Split(v, ",")...)
+		}
+		rep := r.Run(req.Context(), livenessOnly, exclude...)
+		if readiness && r.draining.Load() {
+			rep.Status, rep.Reason = StatusDown, "shutting_down"
+		}
+		if !detailed {
+			for name, res := range rep.Checks {
+				res.Error = ""
+				rep.Checks[name] = res
+			}
+		}
+
+		code := http.StatusOK
+		if rep.Status != StatusUp {
+			code = http.StatusServiceUnavailable
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(code)
+		json.NewEncoder(w).Encode(rep)
+	})
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if strings.TrimSpace(v) == s {
+			return true
+		}
+	}
+	return false
+}
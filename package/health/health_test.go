@@ -0,0 +1,74 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const dialErr = "dial tcp 10.0.0.5:3306: connect: connection refused"
+
+func TestPublicHandlersHideErrors(t *testing.T) {
+	reg := New(0, 0)
+	reg.Register(Check{Name: "mysql", Func: func(context.Context) error { return errors.New(dialErr) }, Liveness: true})
+	reg.Register(Check{Name: "disk", Func: func(context.Context) error { return nil }, Liveness: true})
+
+	tests := []struct {
+		name    string
+		handler http.Handler
+		wantErr string
+	}{
+		{"livez", reg.Livez(), ""},
+		{"readyz", reg.Readyz(), ""},
+		{"healthz", reg.Healthz(), ""},
+		{"details", reg.Details(), dialErr},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			tt.handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+			if w.Code != http.StatusServiceUnavailable {
+				t.Errorf("status = %d, muốn 503", w.Code)
+			}
+			var rep Report
+			if err := json.NewDecoder(w.Body).Decode(&rep); err != nil {
+				t.Fatal(err)
+			}
+			got := rep.Checks["mysql"]
+			if got.Status != StatusDown {
+				t.Errorf("mysql status = %q, muốn down", got.Status)
+			}
+			if got.Error != tt.wantErr {
+				t.Errorf("mysql error = %q, muốn %q", got.Error, tt.wantErr)
+			}
+			if rep.Checks["disk"].Status != StatusUp {
+				t.Errorf("disk status = %q, muốn up", rep.Checks["disk"].Status)
+			}
+		})
+	}
+}
+
+func TestLogOnStatusChange(t *testing.T) {
+	var fail error
+	var logs []string
+	reg := New(0, 0)
+	reg.Logf = func(format string, args ...any) { logs = append(logs, fmt.Sprintf(format, args...)) }
+	reg.Register(Check{Name: "mysql", Func: func(context.Context) error { return fail }})
+
+	steps := []error{nil, errors.New(dialErr), errors.New(dialErr), nil, nil}
+	for _, err := range steps {
+		fail = err
+		reg.Run(context.Background(), false)
+	}
+	want := []string{
+		"Health check mysql down: " + dialErr,
+		"Health check mysql up trở lại",
+	}
+	if fmt.Sprint(logs) != fmt.Sprint(want) {
+		t.Errorf("logs = %q, muốn %q", logs, want)
+	}
+}
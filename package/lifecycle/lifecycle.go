@@ -0,0 +1,138 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Hook là một thành phần có vòng đời. OnStart chạy theo thứ tự đăng ký,
+// OnStop chạy theo thứ tự ngược lại (thành phần khởi động sau dừng trước).
+type Hook struct {
+	Name    string
+	OnStart func(ctx context.Context) error
+	OnStop  func(ctx context.Context) error
+}
+
+// Manager quản lý khởi động và tắt ứng dụng có trật tự
+type Manager struct {
+	// ShutdownTimeout là tổng thời gian cho phép để chạy hết các OnStop
+	ShutdownTimeout time.Duration
+	// Logf nhận thông báo tiến trình khởi động/tắt (có thể nil)
+	Logf func(format string, args ...any)
+
+	mu      sync.Mutex
+	hooks   []Hook
+	started int
+	failed  chan error
+	once    sync.Once
+}
+
+// New tạo Manager với thời gian tắt tối đa shutdownTimeout
+func New(shutdownTimeout time.Duration) *Manager {
+	return &Manager{
+		ShutdownTimeout: shutdownTimeout,
+		failed:          make(chan error, 1),
+	}
+}
+
+// Append đăng ký thêm một hook
+func (m *Manager) Append(h Hook) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.hooks = append(m.hooks, h)
+}
+
+// Fail báo một thành phần đã chết khi đang chạy (ví dụ Serve trả lỗi),
+// khiến Run dừng toàn bộ ứng dụng. Chỉ lỗi đầu tiên được giữ lại.
+func (m *Manager) Fail(err error) {
+	m.once.Do(func() { m.failed <- err })
+}
+
+// Start chạy OnStart của các hook theo thứ tự. Nếu một hook lỗi, các hook
+// đã khởi động trước đó được dừng lại theo thứ tự ngược.
+func (m *Manager) Start(ctx context.Context) error {
+	m.mu.Lock()
+	hooks := m.hooks
+	m.mu.Unlock()
+
+	for i, h := range hooks {
+		if h.OnStart != nil {
+			m.logf("Khởi động %s...", h.Name)
+			if err := h.OnStart(ctx); err != nil {
+				m.mu.Lock()
+				m.started = i
+				m.mu.Unlock()
+				stopErr := m.Stop(context.Background())
+				return errors.Join(fmt.Errorf("khởi động %s thất bại: %w", h.Name, err), stopErr)
+			}
+		}
+	}
+	m.mu.Lock()
+	m.started = len(hooks)
+	m.mu.Unlock()
+	return nil
+}
+
+// Stop chạy OnStop của các hook đã khởi động theo thứ tự ngược, trong giới
+// hạn ShutdownTimeout. Lỗi của từng hook được gom lại, không dừng giữa chừng.
+func (m *Manager) Stop(ctx context.Context) error {
+	m.mu.Lock()
+	hooks := m.hooks[:m.started]
+	m.started = 0
+	m.mu.Unlock()
+
+	if m.ShutdownTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, m.ShutdownTimeout)
+		defer cancel()
+	}
+
+	var errs []error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		h := hooks[i]
+		if h.OnStop == nil {
+			continue
+		}
+		m.logf("Dừng %s...", h.Name)
+		if err := h.OnStop(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("dừng %s thất bại: %w", h.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Run khởi động mọi hook, chờ SIGINT/SIGTERM, ctx bị hủy hoặc một thành
+// phần gọi Fail, rồi tắt ứng dụng. Trả về lỗi của Fail (nếu có) gộp với lỗi khi dừng.
+func (m *Manager) Run(ctx context.Context) error {
+	if err := m.Start(ctx); err != nil {
+		return err
+	}
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigs)
+
+	var runErr error
+	select {
+	case sig := <-sigs:
+		m.logf("Nhận tín hiệu %s, bắt đầu tắt ứng dụng.", sig)
+	case <-ctx.Done():
+		m.logf("Context bị hủy, bắt đầu tắt ứng dụng.")
+	case runErr = <-m.failed:
+		m.logf("Một thành phần bị lỗi (%v), bắt đầu tắt ứng dụng.", runErr)
+	}
+
+	return errors.Join(runErr, m.Stop(context.Background()))
+}
+
+func (m *Manager) logf(format string, args ...any) {
+	if m.Logf != nil {
+		m.Logf(format, args...)
+	}
+}
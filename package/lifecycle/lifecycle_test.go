@@ -0,0 +1,147 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recorder ghi lại thứ tự gọi OnStart/OnStop của các hook giả
+type recorder struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (r *recorder) add(s string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, s)
+}
+
+func (r *recorder) String() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return fmt.Sprint(r.calls)
+}
+
+// hook tạo hook giả; startErr/stopErr khác nil thì OnStart/OnStop trả lỗi đó
+func (r *recorder) hook(name string, startErr, stopErr error) Hook {
+	return Hook{
+		Name: name,
+		OnStart: func(context.Context) error {
+			r.add("start " + name)
+			return startErr
+		},
+		OnStop: func(context.Context) error {
+			r.add("stop " + name)
+			return stopErr
+		},
+	}
+}
+
+func TestStartRollback(t *testing.T) {
+	errBoom := errors.New("boom")
+	errStop := errors.New("stop a")
+	rec := &recorder{}
+	m := New(time.Second)
+	m.Append(rec.hook("a", nil, errStop))
+	m.Append(rec.hook("b", nil, nil))
+	m.Append(Hook{Name: "no-stop", OnStart: func(context.Context) error { rec.add("start no-stop"); return nil }})
+	m.Append(rec.hook("c", errBoom, nil))
+	m.Append(rec.hook("d", nil, nil))
+
+	err := m.Start(context.Background())
+	if !errors.Is(err, errBoom) || !errors.Is(err, errStop) {
+		t.Errorf("err = %v, muốn gộp lỗi khởi động c và lỗi dừng a", err)
+	}
+	// c lỗi nên không được dừng, d chưa khởi động; b và a dừng theo thứ tự ngược
+	want := "[start a start b start no-stop start c stop b stop a]"
+	if got := rec.String(); got != want {
+		t.Errorf("thứ tự gọi = %s, muốn %s", got, want)
+	}
+
+	// Stop lần nữa không dừng lại các hook đã rollback
+	if err := m.Stop(context.Background()); err != nil {
+		t.Errorf("Stop sau rollback: err = %v", err)
+	}
+	if got := rec.String(); got != want {
+		t.Errorf("thứ tự gọi sau Stop = %s, muốn %s", got, want)
+	}
+}
+
+func TestRunStopsOnFail(t *testing.T) {
+	errDied := errors.New("serve: listener closed")
+	rec := &recorder{}
+	m := New(time.Second)
+	m.Append(rec.hook("db", nil, nil))
+	m.Append(Hook{
+		Name: "http",
+		OnStart: func(context.Context) error {
+			rec.add("start http")
+			// Thành phần chết sau khi đã khởi động; chỉ lỗi đầu tiên được giữ
+			go func() {
+				m.Fail(errDied)
+				m.Fail(errors.New("lỗi thứ hai"))
+			}()
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			rec.add("stop http")
+			return nil
+		},
+	})
+
+	done := make(chan error, 1)
+	go func() { done <- m.Run(context.Background()) }()
+	select {
+	case err := <-done:
+		if !errors.Is(err, errDied) {
+			t.Errorf("Run: err = %v, muốn %v", err, errDied)
+		}
+		if got := err.Error(); got != errDied.Error() {
+			t.Errorf("Run: err = %q, muốn chỉ có lỗi đầu tiên", got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run không dừng sau Fail")
+	}
+	want := "[start db start http stop http stop db]"
+	if got := rec.String(); got != want {
+		t.Errorf("thứ tự gọi = %s, muốn %s", got, want)
+	}
+}
+
+func TestRunStopsOnContextCancel(t *testing.T) {
+	rec := &recorder{}
+	m := New(time.Second)
+	m.Append(rec.hook("a", nil, nil))
+	m.Append(rec.hook("b", nil, nil))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := m.Run(ctx); err != nil {
+		t.Errorf("Run: err = %v, muốn nil", err)
+	}
+	want := "[start a start b stop b stop a]"
+	if got := rec.String(); got != want {
+		t.Errorf("thứ tự gọi = %s, muốn %s", got, want)
+	}
+}
+
+func TestRunStartFailure(t *testing.T) {
+	errBoom := errors.New("boom")
+	rec := &recorder{}
+	m := New(time.Second)
+	m.Append(rec.hook("a", nil, nil))
+	m.Append(rec.hook("b", errBoom, nil))
+
+	if err := m.Run(context.Background()); !errors.Is(err, errBoom) {
+		t.Errorf("Run: err = %v, muốn %v", err, errBoom)
+	}
+	want := "[start a start b stop a]"
+	if got := rec.String(); got != want {
+		t.Errorf("thứ tự gọi = %s, muốn %s", got, want)
+	}
+}
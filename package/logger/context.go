@@ -0,0 +1,48 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"vadilatorgolang/package/requestid"
+)
+
+type ctxKey struct{}
+
+// NewContext gắn logger con l vào ctx
+func NewContext(ctx context.Context, l *slog.Logger) context.Context {
+	return context.WithValue(ctx, ctxKey{}, l)
+}
+
+// FromContext lấy logger gắn trong ctx, nếu không có thì trả về logger gốc
+func FromContext(ctx context.Context) *slog.Logger {
+	if l, ok := ctx.Value(ctxKey{}).(*slog.Logger); ok {
+		return l
+	}
+	return Default()
+}
+
+// ForRequest tạo logger con mang request ID, method và path của r
+func ForRequest(r *http.Request) *slog.Logger {
+	attrs := make([]any, 0, 3)
+	if id := requestid.FromRequest(r); id != "" {
+		attrs = append(attrs, slog.String("request_id", id))
+	}
+	attrs = append(attrs, slog.String("method", r.Method), slog.String("path", r.URL.Path))
+	return Default().With(attrs...)
+}
+
+// FromRequest lấy logger của request trong context, hoặc tạo mới bằng ForRequest
+func FromRequest(r *http.Request) *slog.Logger {
+	if l, ok := r.Context().Value(ctxKey{}).(*slog.Logger); ok {
+		return l
+	}
+	return ForRequest(r)
+}
+
+// WithUserID thêm user_id vào logger của request và trả về request mới mang logger đó
+func WithUserID(r *http.Request, id int) *http.Request {
+	l := FromRequest(r).With(slog.Int("user_id", id))
+	return r.WithContext(NewContext(r.Context(), l))
+}
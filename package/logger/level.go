@@ -0,0 +1,200 @@
+package logger
+
+import (
+	"log/slog"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Mức log có thể đổi lúc đang chạy: một mức gốc (từ cấu hình) cộng với các
+// mức ghi đè theo component, mỗi mức ghi đè có thể kèm thời hạn.
+// Component là đường dẫn package tính từ module, ví dụ "internal/user";
+// ghi đè cho "internal" áp dụng cho mọi package con, khớp dài nhất thắng.
+// Component rỗng "" là ghi đè cho toàn bộ ứng dụng.
+
+type levelOverride struct {
+	level slog.Level
+	until time.Time // zero = không hết hạn
+}
+
+var (
+	levelMu   sync.RWMutex
+	baseLevel = LevelInfo
+	overrides = map[string]levelOverride{}
+	// lowest là mức thấp nhất trong base và mọi ghi đè, dùng để loại nhanh
+	// bản ghi trong Enabled trước khi biết bản ghi thuộc component nào
+	lowest atomic.Int64
+
+	componentCache sync.Map // pc → component
+	modulePrefix   = func() string {
+		if bi, ok := debug.ReadBuildInfo(); ok && bi.Main.Path != "" {
+			return bi.Main.Path + "/"
+		}
+		return "vadilatorgolang/"
+	}()
+)
+
+// LevelState mô tả một mức ghi đè đang có hiệu lực
+type LevelState struct {
+	Component string    `json:"component"`
+	Level     string    `json:"level"`
+	Until     time.Time `json:"until,omitempty"`
+}
+
+// setBaseLevel đặt mức gốc lấy từ cấu hình
+func setBaseLevel(l slog.Level) {
+	levelMu.Lock()
+	defer levelMu.Unlock()
+	baseLevel = l
+	recomputeLowest()
+}
+
+// SetLevel ghi đè mức log cho component (rỗng = toàn bộ ứng dụng).
+// ttl > 0 thì ghi đè tự hết hiệu lực sau ttl.
+func SetLevel(component string, l slog.Level, ttl time.Duration) {
+	component = strings.Trim(component, "/")
+	o := levelOverride{level: l}
+	if ttl > 0 {
+		o.until = time.Now().Add(ttl)
+	}
+	levelMu.Lock()
+	defer levelMu.Unlock()
+	overrides[component] = o
+	recomputeLowest()
+}
+
+// ResetLevel bỏ ghi đè của component, quay về mức của cha hoặc mức gốc
+func ResetLevel(component string) {
+	levelMu.Lock()
+	defer levelMu.Unlock()
+	delete(overrides, strings.Trim(component, "/"))
+	recomputeLowest()
+}
+
+// Level trả về mức hiện tại của toàn bộ ứng dụng
+func Level() slog.Level { return LevelFor("") }
+
+// BaseLevel trả về mức gốc lấy từ cấu hình
+func BaseLevel() slog.Level {
+	levelMu.RLock()
+	defer levelMu.RUnlock()
+	return baseLevel
+}
+
+// LevelFor trả về mức hiệu lực của component
+func LevelFor(component string) slog.Level {
+	levelMu.RLock()
+	level, expired := levelForLocked(component)
+	levelMu.RUnlock()
+	if expired {
+		pruneExpired()
+		return LevelFor(component)
+	}
+	return level
+}
+
+func levelForLocked(component string) (slog.Level, bool) {
+	now := time.Now()
+	best, bestLen := baseLevel, -1
+	for c, o := range overrides {
+		if c != "" && component != c && !strings.HasPrefix(component, c+"/") {
+			continue
+		}
+		if !o.until.IsZero() && now.After(o.until) {
+			return 0, true
+		}
+		if len(c) > bestLen {
+			best, bestLen = o.level, len(c)
+		}
+	}
+	return best, false
+}
+
+// Levels trả về mức gốc và danh sách ghi đè còn hiệu lực
+func Levels() (base slog.Level, active []LevelState) {
+	pruneExpired()
+	levelMu.RLock()
+	defer levelMu.RUnlock()
+	for c, o := range overrides {
+		active = append(active, LevelState{Component: c, Level: strings.ToLower(LevelName(o.level)), Until: o.until})
+	}
+	sort.Slice(active, func(i, j int) bool { return active[i].Component < active[j].Component })
+	return baseLevel, active
+}
+
+func pruneExpired() {
+	levelMu.Lock()
+	defer levelMu.Unlock()
+	now := time.Now()
+	for c, o := range overrides {
+		if !o.until.IsZero() && now.After(o.until) {
+			delete(overrides, c)
+		}
+	}
+	recomputeLowest()
+}
+
+// recomputeLowest phải được gọi khi đang giữ levelMu
+func recomputeLowest() {
+	low := baseLevel
+	for _, o := range overrides {
+		if o.level < low {
+			low = o.level
+		}
+	}
+	lowest.Store(int64(low))
+}
+
+// stepDown trả về mức chi tiết hơn l một bậc (ERROR → WARN → ... → TRACE)
+func stepDown(l slog.Level) slog.Level {
+	switch {
+	case l > LevelWarn:
+		return LevelWarn
+	case l > LevelInfo:
+		return LevelInfo
+	case l > LevelDebug:
+		return LevelDebug
+	}
+	return LevelTrace
+}
+
+// maybeEnabled là kiểm tra nhanh: false nghĩa là chắc chắn không ghi
+func maybeEnabled(l slog.Level) bool {
+	return l >= slog.Level(lowest.Load())
+}
+
+// enabledAt kiểm tra chính xác theo component của dòng code đã ghi log
+func enabledAt(l slog.Level, pc uintptr) bool {
+	if !maybeEnabled(l) {
+		return false
+	}
+	return l >= LevelFor(componentOf(pc))
+}
+
+// componentOf suy ra component từ hàm chứa pc, ví dụ
+// "vadilatorgolang/internal/user.(*UserHandler).CreateUserHandler" → "internal/user"
+func componentOf(pc uintptr) string {
+	if pc == 0 {
+		return ""
+	}
+	if c, ok := componentCache.Load(pc); ok {
+		return c.(string)
+	}
+	fn := runtime.FuncForPC(pc)
+	if fn == nil {
+		return ""
+	}
+	name := fn.Name()
+	slash := strings.LastIndex(name, "/")
+	if dot := strings.Index(name[slash+1:], "."); dot >= 0 {
+		name = name[:slash+1+dot]
+	}
+	name = strings.TrimPrefix(name, modulePrefix)
+	componentCache.Store(pc, name)
+	return name
+}
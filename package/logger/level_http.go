@@ -0,0 +1,77 @@
+package logger
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"vadilatorgolang/package/apperr"
+)
+
+// levelRequest là body của PUT/POST lên endpoint mức log
+type levelRequest struct {
+	Component string `json:"component"`
+	Level     string `json:"level"`
+	TTL       string `json:"ttl"` // ví dụ "10m"; rỗng = không hết hạn
+}
+
+type levelResponse struct {
+	Base      string       `json:"base"`
+	Effective string       `json:"effective"`
+	Overrides []LevelState `json:"overrides"`
+}
+
+// LevelHandler trả về handler quản lý mức log lúc đang chạy:
+//
+//	GET                         xem mức gốc và các ghi đè
+//	PUT {component,level,ttl}   đặt mức cho component ("" = toàn bộ)
+//	DELETE ?component=...       bỏ ghi đè của component
+//
+// Handler không tự xác thực; nơi đăng ký phải bọc nó bằng middleware xác thực.
+func LevelHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut, http.MethodPost:
+			var req levelRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				apperr.WriteProblem(w, r, apperr.BadRequest("invalid_body", "Request body không hợp lệ").Wrap(err))
+				return
+			}
+			level, err := ParseLevel(req.Level)
+			if err != nil || req.Level == "" {
+				apperr.WriteProblem(w, r, apperr.BadRequest("invalid_level", "Mức log phải là trace, debug, info, warn hoặc error"))
+				return
+			}
+			var ttl time.Duration
+			if req.TTL != "" {
+				if ttl, err = time.ParseDuration(req.TTL); err != nil || ttl < 0 {
+					apperr.WriteProblem(w, r, apperr.BadRequest("invalid_ttl", "ttl không hợp lệ, ví dụ: 10m"))
+					return
+				}
+			}
+			SetLevel(req.Component, level, ttl)
+			FromRequest(r).Warn("Đã đổi mức log", "component", req.Component, "level", LevelName(level), "ttl", ttl.String())
+		case http.MethodDelete:
+			component := r.URL.Query().Get("component")
+			ResetLevel(component)
+			FromRequest(r).Warn("Đã bỏ ghi đè mức log", "component", component)
+		default:
+			w.Header().Set("Allow", "GET, PUT, POST, DELETE")
+			apperr.WriteProblem(w, r, apperr.New(apperr.KindMethodNotAllowed, "method_not_allowed", "Method không được hỗ trợ"))
+			return
+		}
+
+		base, active := Levels()
+		if active == nil {
+			active = []LevelState{}
+		}
+		w.Header().Set("content-type", "application/json")
+		json.NewEncoder(w).Encode(levelResponse{
+			Base:      strings.ToLower(LevelName(base)),
+			Effective: strings.ToLower(LevelName(Level())),
+			Overrides: active,
+		})
+	})
+}
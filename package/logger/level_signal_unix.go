@@ -0,0 +1,38 @@
+//go:build !windows
+
+package logger
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// LevelSignals cho phép đổi mức log bằng tín hiệu:
+// SIGUSR1 hạ mức toàn cục một bậc (chi tiết hơn, tới TRACE),
+// SIGUSR2 bỏ mọi ghi đè toàn cục, quay về mức trong cấu hình.
+// Gọi hàm stop trả về để ngừng lắng nghe.
+func LevelSignals() (stop func()) {
+	ch := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(ch, syscall.SIGUSR1, syscall.SIGUSR2)
+	go func() {
+		for {
+			select {
+			case sig := <-ch:
+				if sig == syscall.SIGUSR2 {
+					ResetLevel("")
+				} else {
+					SetLevel("", stepDown(Level()), 0)
+				}
+				Default().Warn("Đã đổi mức log toàn cục", "signal", sig.String(), "level", LevelName(Level()))
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(ch)
+		close(done)
+	}
+}
@@ -0,0 +1,9 @@
+//go:build windows
+
+package logger
+
+// LevelSignals không làm gì trên Windows vì không có SIGUSR1/SIGUSR2;
+// dùng endpoint quản trị để đổi mức log.
+func LevelSignals() (stop func()) {
+	return func() {}
+}
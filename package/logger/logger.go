@@ -1,66 +1,153 @@
 package logger
 
 import (
+	"errors"
 	"io"
 	"log"
+	"log/slog"
 	"os"
+	"os/signal"
+	"path/filepath"
+
+	"vadilatorgolang/package/config"
 )
 
 var (
+	// Các logger kiểu cũ được giữ lại để code hiện có (logger.InfoLogger.Printf...)
+	// vẫn chạy. Mỗi dòng được chuyển thành một bản ghi slog ở đúng mức độ.
 	TraceLogger *log.Logger
 	DebugLogger *log.Logger
 	InfoLogger  *log.Logger
 	WarnLogger  *log.Logger
 	ErrorLogger *log.Logger
+
+	// files giữ các file log đang mở để Close/Reopen có thể thao tác
+	files []*RotatingFile
 )
 
-// InitLoggers khởi tạo các logger theo từng mức độ
-func InitLoggers() {
+// InitLoggers khởi tạo logger có cấu trúc (slog) và các logger theo từng mức độ.
+// Mỗi mức độ vẫn ghi vào file riêng trong cfg.Dir như trước.
+func InitLoggers(cfg config.LogConfig) {
 	// Tạo thư mục log nếu chưa có
-	if _, err := os.Stat("log"); os.IsNotExist(err) {
-		os.Mkdir("log", 0755)
+	if _, err := os.Stat(cfg.Dir); os.IsNotExist(err) {
+		os.MkdirAll(cfg.Dir, 0755)
 	}
 
-	// TRACE 
-	traceFile, err := os.OpenFile("log/trace.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0666)
+	level, err := ParseLevel(cfg.Level)
 	if err != nil {
-		log.Fatal("Không thể mở file log/trace.log:", err)
+		log.Fatal("Mức log không hợp lệ:", err)
 	}
-	traceWriter := io.MultiWriter(traceFile, os.Stdout)
-	TraceLogger = log.New(traceWriter, "TRACE: ", log.Ldate|log.Ltime|log.Lshortfile)
+	setBaseLevel(level)
+	SetRedactRules(cfg.Redact)
+	format = cfg.Format
 
-	// DEBUG 
-	debugFile, err := os.OpenFile("log/debug.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0666)
-	if err != nil {
-		log.Fatal("Không thể mở file log/debug.log:", err)
-	}
-	debugWriter := io.MultiWriter(debugFile, os.Stdout)
-	DebugLogger = log.New(debugWriter, "DEBUG: ", log.Ldate|log.Ltime|log.Lshortfile)
+	// TRACE
+	traceWriter := openLevelFile(cfg, "trace.log", os.Stdout)
+	// DEBUG
+	debugWriter := openLevelFile(cfg, "debug.log", os.Stdout)
+	// INFO
+	infoWriter := openLevelFile(cfg, "info.log", os.Stdout)
+	// WARN
+	warnWriter := openLevelFile(cfg, "warn.log", os.Stdout)
+	// ERROR
+	errorWriter := openLevelFile(cfg, "error.log", os.Stderr)
+
+	rootHandler.Store(&levelRouter{
+		trace: newEncoder(cfg.Format, traceWriter),
+		debug: newEncoder(cfg.Format, debugWriter),
+		info:  newEncoder(cfg.Format, infoWriter),
+		warn:  newEncoder(cfg.Format, warnWriter),
+		error: newEncoder(cfg.Format, errorWriter),
+	})
+	slog.SetDefault(slog.New(&handlerProxy{}))
+
+	TraceLogger = newCompatLogger(LevelTrace)
+	DebugLogger = newCompatLogger(LevelDebug)
+	InfoLogger = newCompatLogger(LevelInfo)
+	WarnLogger = newCompatLogger(LevelWarn)
+	ErrorLogger = newCompatLogger(LevelError)
 
-	// INFO 
-	infoFile, err := os.OpenFile("log/info.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0666)
+	// Ghi log khi khởi tạo thành công
+	InfoLogger.Println("Logger hệ thống đã được khởi tạo thành công.")
+}
+
+// openLevelFile mở file log của một mức độ, ghi kèm ra console nếu được bật
+func openLevelFile(cfg config.LogConfig, name string, console io.Writer) io.Writer {
+	f, err := OpenRotatingFile(filepath.Join(cfg.Dir, name), cfg.Rotate)
 	if err != nil {
-		log.Fatal("Không thể mở file log/info.log:", err)
+		log.Fatal("Không thể mở file "+name+":", err)
+	}
+	files = append(files, f)
+	if !cfg.Console {
+		return f
 	}
-	infoWriter := io.MultiWriter(infoFile, os.Stdout)
-	InfoLogger = log.New(infoWriter, "INFO: ", log.Ldate|log.Ltime|log.Lshortfile)
+	return io.MultiWriter(f, console)
+}
 
-	// WARN 
-	warnFile, err := os.OpenFile("log/warn.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0666)
-	if err != nil {
-		log.Fatal("Không thể mở file log/warn.log:", err)
+// OpenAccessLog mở file access log trong thư mục log. File được xoay vòng,
+// mở lại khi SIGHUP và đóng khi Close như các file log khác.
+func OpenAccessLog(cfg config.LogConfig) io.Writer {
+	return openLevelFile(cfg, cfg.Access.File, os.Stdout)
+}
+
+// Reopen mở lại mọi file log theo đường dẫn, dùng sau khi logrotate
+// bên ngoài đã đổi tên file
+func Reopen() error {
+	var errs []error
+	for _, f := range files {
+		if err := f.Reopen(); err != nil {
+			errs = append(errs, err)
+		}
 	}
-	warnWriter := io.MultiWriter(warnFile, os.Stdout)
-	WarnLogger = log.New(warnWriter, "WARN: ", log.Ldate|log.Ltime|log.Lshortfile)
+	return errors.Join(errs...)
+}
 
-	// ERROR 
-	errorFile, err := os.OpenFile("log/error.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0666)
-	if err != nil {
-		log.Fatal("Không thể mở file log/error.log:", err)
+// ReopenOnSignal gọi Reopen mỗi khi nhận một trong các tín hiệu sigs
+// (thường là SIGHUP). Gọi hàm stop trả về để ngừng lắng nghe.
+func ReopenOnSignal(sigs ...os.Signal) (stop func()) {
+	ch := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(ch, sigs...)
+	go func() {
+		for {
+			select {
+			case sig := <-ch:
+				if err := Reopen(); err != nil {
+					Default().Error("Không thể mở lại file log", "signal", sig.String(), "error", err)
+				} else {
+					Default().Info("Đã mở lại file log", "signal", sig.String())
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(ch)
+		close(done)
 	}
-	errorWriter := io.MultiWriter(errorFile, os.Stderr)
-	ErrorLogger = log.New(errorWriter, "ERROR: ", log.Ldate|log.Ltime|log.Lshortfile)
+}
 
-	// Ghi log khi khởi tạo thành công
-	InfoLogger.Println("Logger hệ thống đã được khởi tạo thành công.")
+// Close ghi hết dữ liệu xuống đĩa và đóng các file log. Sau khi Close,
+// mọi log chỉ còn ghi ra stdout/stderr.
+func Close() error {
+	rootHandler.Store(&levelRouter{
+		trace: newEncoder(format, os.Stdout),
+		debug: newEncoder(format, os.Stdout),
+		info:  newEncoder(format, os.Stdout),
+		warn:  newEncoder(format, os.Stdout),
+		error: newEncoder(format, os.Stderr),
+	})
+
+	var errs []error
+	for _, f := range files {
+		if err := f.Sync(); err != nil {
+			errs = append(errs, err)
+		}
+		if err := f.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	files = nil
+	return errors.Join(errs...)
 }
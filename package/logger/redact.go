@@ -0,0 +1,261 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"path"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync/atomic"
+
+	"vadilatorgolang/package/config"
+)
+
+// Quy tắc che dữ liệu cá nhân (PII) trong log.
+//
+// Trên struct dùng tag `log:"redact"` (bỏ hẳn giá trị) hoặc `log:"mask"`
+// (che một phần, email thành k***@example.com). Trường không có tag được
+// so tên (không phân biệt hoa thường) với các mẫu glob trong cấu hình.
+// Quy tắc áp dụng cho field có cấu trúc (slog), cho logger.Redact(v) dùng
+// với %v/%+v, và email xuất hiện trong nội dung message cũng bị che.
+
+const redactedValue = "[REDACTED]"
+
+type redactAction uint8
+
+const (
+	actionKeep redactAction = iota
+	actionMask
+	actionRedact
+)
+
+type redactRules struct {
+	redact        []string
+	mask          []string
+	scrubMessages bool
+}
+
+var (
+	rules      atomic.Pointer[redactRules]
+	emailRegex = regexp.MustCompile(`([A-Za-z0-9._%+\-])[A-Za-z0-9._%+\-]*@([A-Za-z0-9.\-]+\.[A-Za-z]{2,})`)
+)
+
+func init() {
+	SetRedactRules(config.Default().Log.Redact)
+}
+
+// SetRedactRules thay bộ quy tắc che dữ liệu đang dùng
+func SetRedactRules(cfg config.LogRedactConfig) {
+	r := &redactRules{scrubMessages: cfg.ScrubMessages}
+	for _, p := range cfg.RedactFields {
+		r.redact = append(r.redact, strings.ToLower(p))
+	}
+	for _, p := range cfg.MaskFields {
+		r.mask = append(r.mask, strings.ToLower(p))
+	}
+	rules.Store(r)
+}
+
+// actionFor quyết định cách xử lý trường name; tag (nếu có) được ưu tiên
+func actionFor(name, tag string) redactAction {
+	switch tag {
+	case "redact":
+		return actionRedact
+	case "mask":
+		return actionMask
+	case "keep":
+		return actionKeep
+	}
+	r := rules.Load()
+	name = strings.ToLower(name)
+	for _, p := range r.redact {
+		if ok, _ := path.Match(p, name); ok {
+			return actionRedact
+		}
+	}
+	for _, p := range r.mask {
+		if ok, _ := path.Match(p, name); ok {
+			return actionMask
+		}
+	}
+	return actionKeep
+}
+
+// MaskString che một phần chuỗi: email giữ ký tự đầu và tên miền
+// (k***@example.com), chuỗi khác chỉ giữ ký tự đầu
+func MaskString(s string) string {
+	if s == "" {
+		return ""
+	}
+	if at := strings.LastIndexByte(s, '@'); at > 0 {
+		return s[:1] + "***" + s[at:]
+	}
+	if len(s) <= 2 {
+		return "***"
+	}
+	return s[:1] + "***"
+}
+
+// scrubMessage che mọi email xuất hiện trong message (ví dụ log cũ dùng %+v)
+func scrubMessage(msg string) string {
+	if !rules.Load().scrubMessages || !strings.Contains(msg, "@") {
+		return msg
+	}
+	return emailRegex.ReplaceAllString(msg, "$1***@$2")
+}
+
+// redactAttr áp dụng quy tắc cho một attr của slog
+func redactAttr(a slog.Attr) slog.Attr {
+	if _, ok := a.Value.Any().(keptValue); ok && a.Value.Kind() == slog.KindAny {
+		return a
+	}
+	switch actionFor(a.Key, "") {
+	case actionRedact:
+		return slog.Attr{}
+	case actionMask:
+		return slog.String(a.Key, MaskString(a.Value.Resolve().String()))
+	}
+	if a.Value.Kind() == slog.KindAny {
+		if v, ok := redactReflect(reflect.ValueOf(a.Value.Any()), 0); ok {
+			a.Value = v
+		}
+	}
+	return a
+}
+
+// redactReflect chuyển struct/map thành group đã che; ok = false nếu v là giá
+// trị lá (số, chuỗi, time.Time, Stringer, error...) cần giữ nguyên
+func redactReflect(v reflect.Value, depth int) (slog.Value, bool) {
+	if depth > 8 || !v.IsValid() {
+		return slog.Value{}, false
+	}
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return slog.Value{}, false
+		}
+		v = v.Elem()
+	}
+	if isLeaf(v) {
+		return slog.Value{}, false
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		t := v.Type()
+		attrs := make([]slog.Attr, 0, t.NumField())
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			if a := redactField(fieldName(f), f.Tag.Get("log"), v.Field(i), depth); a.Key != "" {
+				attrs = append(attrs, a)
+			}
+		}
+		return slog.GroupValue(attrs...), true
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return slog.Value{}, false
+		}
+		attrs := make([]slog.Attr, 0, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			if a := redactField(iter.Key().String(), "", iter.Value(), depth); a.Key != "" {
+				attrs = append(attrs, a)
+			}
+		}
+		return slog.GroupValue(attrs...), true
+	}
+	return slog.Value{}, false
+}
+
+func redactField(name, tag string, v reflect.Value, depth int) slog.Attr {
+	switch actionFor(name, tag) {
+	case actionRedact:
+		return slog.Attr{}
+	case actionMask:
+		return slog.String(name, MaskString(fmt.Sprint(v.Interface())))
+	}
+	if tag == "keep" {
+		return slog.Any(name, keptValue{v: v.Interface()})
+	}
+	if nested, ok := redactReflect(v, depth+1); ok {
+		return slog.Attr{Key: name, Value: nested}
+	}
+	return slog.Any(name, v.Interface())
+}
+
+// keptValue đánh dấu giá trị giữ nguyên theo tag `log:"keep"`. Handler gọi
+// replaceAttr cả với attr trong group, nên thiếu dấu này thì trường bị che
+// lại theo tên (ví dụ APIToken khớp *token*).
+type keptValue struct{ v any }
+
+func (k keptValue) String() string               { return fmt.Sprint(k.v) }
+func (k keptValue) MarshalJSON() ([]byte, error) { return json.Marshal(k.v) }
+
+var (
+	stringerType = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+	errorType    = reflect.TypeOf((*error)(nil)).Elem()
+	valuerType   = reflect.TypeOf((*slog.LogValuer)(nil)).Elem()
+)
+
+func isLeaf(v reflect.Value) bool {
+	t := v.Type()
+	if t.Implements(stringerType) || t.Implements(errorType) || t.Implements(valuerType) {
+		return true
+	}
+	return v.Kind() != reflect.Struct && v.Kind() != reflect.Map
+}
+
+// fieldName ưu tiên tên trong tag json để khớp với API
+func fieldName(f reflect.StructField) string {
+	if name := strings.SplitN(f.Tag.Get("json"), ",", 2)[0]; name != "" && name != "-" {
+		return name
+	}
+	return f.Name
+}
+
+// Redact bọc v để khi in bằng %v/%+v hoặc ghi làm field slog thì các
+// trường nhạy cảm đã được che theo cùng quy tắc
+func Redact(v any) Redacted { return Redacted{v: v} }
+
+// Redacted là giá trị đã bọc bởi Redact
+type Redacted struct{ v any }
+
+// LogValue cho slog
+func (r Redacted) LogValue() slog.Value {
+	if v, ok := redactReflect(reflect.ValueOf(r.v), 0); ok {
+		return v
+	}
+	return slog.AnyValue(r.v)
+}
+
+// Format cho fmt: %+v in kèm tên trường như fmt gốc, %v chỉ in giá trị
+func (r Redacted) Format(f fmt.State, verb rune) {
+	v := r.LogValue()
+	if v.Kind() != slog.KindGroup {
+		fmt.Fprintf(f, fmt.FormatString(f, verb), r.v)
+		return
+	}
+	writeGroup(f, v.Group(), f.Flag('+'))
+}
+
+func writeGroup(f fmt.State, attrs []slog.Attr, withNames bool) {
+	f.Write([]byte{'{'})
+	for i, a := range attrs {
+		if i > 0 {
+			f.Write([]byte{' '})
+		}
+		if withNames {
+			fmt.Fprintf(f, "%s:", a.Key)
+		}
+		if a.Value.Kind() == slog.KindGroup {
+			writeGroup(f, a.Value.Group(), withNames)
+		} else {
+			fmt.Fprint(f, a.Value.Any())
+		}
+	}
+	f.Write([]byte{'}'})
+}
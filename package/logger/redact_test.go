@@ -0,0 +1,105 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"vadilatorgolang/package/config"
+)
+
+func TestMaskString(t *testing.T) {
+	tests := map[string]string{
+		"":                       "",
+		"khanhchauu@example.com": "k***@example.com",
+		"a@b.co":                 "a***@b.co",
+		"@example.com":           "@***",
+		"0912345678":             "0***",
+		"ab":                     "***",
+	}
+	for in, want := range tests {
+		if got := MaskString(in); got != want {
+			t.Errorf("MaskString(%q) = %q, muốn %q", in, got, want)
+		}
+	}
+}
+
+func TestScrubMessage(t *testing.T) {
+	defer SetRedactRules(config.Default().Log.Redact)
+
+	msg := "Body: {UserName:an Email:an.nguyen@example.com} cc b@x.vn"
+	if got, want := scrubMessage(msg), "Body: {UserName:an Email:a***@example.com} cc b***@x.vn"; got != want {
+		t.Errorf("scrubMessage = %q, muốn %q", got, want)
+	}
+
+	SetRedactRules(config.LogRedactConfig{ScrubMessages: false})
+	if got := scrubMessage(msg); got != msg {
+		t.Errorf("tắt scrub mà vẫn đổi message: %q", got)
+	}
+}
+
+type redactUser struct {
+	Name     string `json:"user_name"`
+	Email    string `log:"mask"`
+	Password string
+	Note     string `log:"redact"`
+	APIToken string `log:"keep"`
+	Profile  struct {
+		Phone string
+	}
+	Meta   map[string]any
+	secret string
+}
+
+func TestRedactAttrs(t *testing.T) {
+	defer SetRedactRules(config.Default().Log.Redact)
+	SetRedactRules(config.Default().Log.Redact)
+
+	u := redactUser{Name: "an", Email: "an@example.com", Password: "p", Note: "n", APIToken: "t", secret: "s"}
+	u.Profile.Phone = "0912345678"
+	u.Meta = map[string]any{"session_token": "x", "ip": "1.2.3.4"}
+
+	var buf bytes.Buffer
+	l := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+		if a.Key == slog.TimeKey {
+			return slog.Attr{}
+		}
+		return redactAttr(a)
+	}}))
+	l.Info("x", "user", &u, "password", "p", "contact_email", "an@example.com")
+
+	got := buf.String()
+	for _, want := range []string{"user.user_name=an", "user.Email=a***@example.com", "user.APIToken=t",
+		"user.Profile.Phone=0***", "user.Meta.ip=1.2.3.4", "contact_email=a***@example.com"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("thiếu %q trong %s", want, got)
+		}
+	}
+	for _, leak := range []string{"Password", "Note", "session_token", "secret", "password="} {
+		if strings.Contains(got, leak) {
+			t.Errorf("lộ %q trong %s", leak, got)
+		}
+	}
+}
+
+func TestRedactFormat(t *testing.T) {
+	defer SetRedactRules(config.Default().Log.Redact)
+	SetRedactRules(config.Default().Log.Redact)
+
+	u := struct {
+		Name     string
+		Email    string
+		Password string
+	}{"an", "an@example.com", "p"}
+	if got, want := fmt.Sprintf("%+v", Redact(u)), "{Name:an Email:a***@example.com}"; got != want {
+		t.Errorf("%%+v = %q, muốn %q", got, want)
+	}
+	if got, want := fmt.Sprintf("%v", Redact(u)), "{an a***@example.com}"; got != want {
+		t.Errorf("%%v = %q, muốn %q", got, want)
+	}
+	if got := fmt.Sprintf("%d", Redact(42)); got != "42" {
+		t.Errorf("giá trị lá = %q", got)
+	}
+}
@@ -0,0 +1,265 @@
+package logger
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"vadilatorgolang/package/config"
+)
+
+// backupTimeFormat là định dạng thời điểm gắn vào tên file đã xoay vòng,
+// ví dụ trace-2025-11-07T16-58-42.000.log (không dùng ':' để chạy được trên Windows)
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// RotatingFile là file log tự xoay vòng theo kích thước và/hoặc thời gian.
+// File cũ được đổi tên kèm thời điểm, nén gzip (nếu bật) và dọn theo
+// MaxAge/MaxBackups ở goroutine nền để không chặn việc ghi log.
+type RotatingFile struct {
+	Path       string
+	MaxSize    int64         // byte, 0 = không xoay theo kích thước
+	Interval   time.Duration // 0 = không xoay theo thời gian
+	Compress   bool
+	MaxAge     time.Duration // 0 = không xóa theo tuổi
+	MaxBackups int           // 0 = không giới hạn số file cũ
+
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	nextTime time.Time
+	millMu   sync.Mutex
+	millWG   sync.WaitGroup
+}
+
+// OpenRotatingFile mở (hoặc tạo) file log tại path với tùy chọn xoay vòng của cfg
+func OpenRotatingFile(path string, cfg config.LogRotateConfig) (*RotatingFile, error) {
+	f := &RotatingFile{
+		Path:       path,
+		MaxSize:    int64(cfg.MaxSizeMB) << 20,
+		Interval:   cfg.Interval,
+		Compress:   cfg.Compress,
+		MaxAge:     cfg.MaxAge,
+		MaxBackups: cfg.MaxBackups,
+	}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *RotatingFile) open() error {
+	file, err := os.OpenFile(f.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0666)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	f.file, f.size = file, info.Size()
+	if f.Interval > 0 {
+		f.nextTime = time.Now().Truncate(f.Interval).Add(f.Interval)
+	}
+	return nil
+}
+
+// Write ghi p, xoay vòng trước nếu vượt kích thước hoặc đã qua mốc thời gian
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		if err := f.open(); err != nil {
+			return 0, err
+		}
+	}
+	if f.shouldRotate(int64(len(p))) {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+func (f *RotatingFile) shouldRotate(next int64) bool {
+	if f.MaxSize > 0 && f.size > 0 && f.size+next > f.MaxSize {
+		return true
+	}
+	return f.Interval > 0 && !time.Now().Before(f.nextTime)
+}
+
+// Rotate xoay vòng ngay lập tức
+func (f *RotatingFile) Rotate() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.rotate()
+}
+
+func (f *RotatingFile) rotate() error {
+	if f.file != nil {
+		if err := f.file.Close(); err != nil {
+			return err
+		}
+		f.file = nil
+	}
+	if _, err := os.Stat(f.Path); err == nil {
+		if err := os.Rename(f.Path, f.backupName(time.Now())); err != nil {
+			return err
+		}
+	}
+	if err := f.open(); err != nil {
+		return err
+	}
+
+	f.millWG.Add(1)
+	go func() {
+		defer f.millWG.Done()
+		f.mill()
+	}()
+	return nil
+}
+
+// Reopen đóng và mở lại file theo đường dẫn. Dùng khi logrotate bên ngoài
+// đã đổi tên file (kết hợp với SIGHUP).
+func (f *RotatingFile) Reopen() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file != nil {
+		f.file.Close()
+		f.file = nil
+	}
+	return f.open()
+}
+
+// Sync ghi dữ liệu đang nằm trong bộ đệm của hệ điều hành xuống đĩa
+func (f *RotatingFile) Sync() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file == nil {
+		return nil
+	}
+	return f.file.Sync()
+}
+
+// Close đóng file và chờ việc nén/dọn dẹp nền kết thúc
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	var err error
+	if f.file != nil {
+		err = f.file.Close()
+		f.file = nil
+	}
+	f.mu.Unlock()
+	f.millWG.Wait()
+	return err
+}
+
+// backupName: log/trace.log → log/trace-<thời điểm>.log
+func (f *RotatingFile) backupName(t time.Time) string {
+	dir, base := filepath.Split(f.Path)
+	ext := filepath.Ext(base)
+	return filepath.Join(dir, strings.TrimSuffix(base, ext)+"-"+t.Format(backupTimeFormat)+ext)
+}
+
+type backup struct {
+	path string
+	at   time.Time
+}
+
+// backups liệt kê các file đã xoay vòng của f, mới nhất trước
+func (f *RotatingFile) backups() ([]backup, error) {
+	dir, base := filepath.Split(f.Path)
+	if dir == "" {
+		dir = "."
+	}
+	ext := filepath.Ext(base)
+	prefix := strings.TrimSuffix(base, ext) + "-"
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var list []backup
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		stamp := strings.TrimPrefix(name, prefix)
+		stamp = strings.TrimSuffix(strings.TrimSuffix(stamp, ".gz"), ext)
+		at, err := time.ParseInLocation(backupTimeFormat, stamp, time.Local)
+		if err != nil {
+			continue
+		}
+		list = append(list, backup{path: filepath.Join(dir, name), at: at})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].at.After(list[j].at) })
+	return list, nil
+}
+
+// mill nén các file cũ chưa nén và xóa file vượt quá MaxAge/MaxBackups
+func (f *RotatingFile) mill() {
+	f.millMu.Lock()
+	defer f.millMu.Unlock()
+
+	list, err := f.backups()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "logger: không thể liệt kê file log cũ:", err)
+		return
+	}
+
+	cutoff := time.Now().Add(-f.MaxAge)
+	for i, b := range list {
+		expired := (f.MaxBackups > 0 && i >= f.MaxBackups) || (f.MaxAge > 0 && b.at.Before(cutoff))
+		if expired {
+			if err := os.Remove(b.path); err != nil && !os.IsNotExist(err) {
+				fmt.Fprintln(os.Stderr, "logger: không thể xóa", b.path+":", err)
+			}
+			continue
+		}
+		if f.Compress && !strings.HasSuffix(b.path, ".gz") {
+			if err := gzipFile(b.path); err != nil {
+				fmt.Fprintln(os.Stderr, "logger: không thể nén", b.path+":", err)
+			}
+		}
+	}
+}
+
+// gzipFile nén src thành src.gz rồi xóa src
+func gzipFile(src string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(src+".gz", os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(out)
+	if _, err := io.Copy(gz, in); err != nil {
+		out.Close()
+		os.Remove(src + ".gz")
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		out.Close()
+		os.Remove(src + ".gz")
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	in.Close()
+	return os.Remove(src)
+}
@@ -0,0 +1,226 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Các mức độ log. TRACE thấp hơn DEBUG của slog một bậc.
+const (
+	LevelTrace = slog.Level(-8)
+	LevelDebug = slog.LevelDebug
+	LevelInfo  = slog.LevelInfo
+	LevelWarn  = slog.LevelWarn
+	LevelError = slog.LevelError
+)
+
+var (
+	// format là encoder đang dùng: "text" hoặc "json"
+	format = "text"
+	// rootHandler là handler gốc, được thay khi InitLoggers/Close
+	rootHandler atomic.Pointer[levelRouter]
+)
+
+// ParseLevel đọc tên mức độ (trace, debug, info, warn, error), không phân biệt hoa thường
+func ParseLevel(s string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "trace":
+		return LevelTrace, nil
+	case "debug":
+		return LevelDebug, nil
+	case "info", "":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "error":
+		return LevelError, nil
+	}
+	return 0, fmt.Errorf("mức log không hợp lệ: %q", s)
+}
+
+// LevelName trả về tên mức độ dạng chữ hoa: TRACE, DEBUG, INFO, WARN, ERROR
+func LevelName(l slog.Level) string {
+	switch {
+	case l < LevelDebug:
+		return "TRACE"
+	case l < LevelInfo:
+		return "DEBUG"
+	case l < LevelWarn:
+		return "INFO"
+	case l < LevelError:
+		return "WARN"
+	}
+	return "ERROR"
+}
+
+// Default trả về logger có cấu trúc gốc của ứng dụng
+func Default() *slog.Logger { return slog.Default() }
+
+// newEncoder tạo handler text hoặc JSON ghi ra w. Handler tự nó không lọc
+// mức độ, việc lọc do levelRouter đảm nhận.
+func newEncoder(fmtName string, w io.Writer) slog.Handler {
+	opts := &slog.HandlerOptions{
+		AddSource:   true,
+		Level:       LevelTrace,
+		ReplaceAttr: replaceAttr,
+	}
+	if fmtName == "json" {
+		return slog.NewJSONHandler(w, opts)
+	}
+	return slog.NewTextHandler(w, opts)
+}
+
+// replaceAttr đổi tên mức TRACE, rút gọn source thành file.go:dòng giống
+// log.Lshortfile và che dữ liệu nhạy cảm trong các field (xem redact.go)
+func replaceAttr(groups []string, a slog.Attr) slog.Attr {
+	if len(groups) > 0 {
+		return redactAttr(a)
+	}
+	switch a.Key {
+	case slog.LevelKey:
+		if l, ok := a.Value.Any().(slog.Level); ok {
+			a.Value = slog.StringValue(LevelName(l))
+		}
+	case slog.SourceKey:
+		if src, ok := a.Value.Any().(*slog.Source); ok {
+			if src.File == "" {
+				return slog.Attr{}
+			}
+			a.Value = slog.StringValue(fmt.Sprintf("%s:%d", filepath.Base(src.File), src.Line))
+		}
+	case slog.TimeKey, slog.MessageKey:
+	default:
+		return redactAttr(a)
+	}
+	return a
+}
+
+// levelRouter chuyển mỗi bản ghi tới handler của đúng mức độ (mỗi mức một file)
+type levelRouter struct {
+	trace, debug, info, warn, error slog.Handler
+}
+
+func (h *levelRouter) pick(l slog.Level) slog.Handler {
+	switch {
+	case l < LevelDebug:
+		return h.trace
+	case l < LevelInfo:
+		return h.debug
+	case l < LevelWarn:
+		return h.info
+	case l < LevelError:
+		return h.warn
+	}
+	return h.error
+}
+
+func (h *levelRouter) Enabled(_ context.Context, l slog.Level) bool {
+	return maybeEnabled(l)
+}
+
+// Handle lọc chính xác theo mức của component (suy ra từ r.PC) rồi mới ghi
+func (h *levelRouter) Handle(ctx context.Context, r slog.Record) error {
+	if !enabledAt(r.Level, r.PC) {
+		return nil
+	}
+	r.Message = scrubMessage(r.Message)
+	return h.pick(r.Level).Handle(ctx, r)
+}
+
+func (h *levelRouter) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return h.each(func(sub slog.Handler) slog.Handler { return sub.WithAttrs(attrs) })
+}
+
+func (h *levelRouter) WithGroup(name string) slog.Handler {
+	return h.each(func(sub slog.Handler) slog.Handler { return sub.WithGroup(name) })
+}
+
+func (h *levelRouter) each(fn func(slog.Handler) slog.Handler) *levelRouter {
+	return &levelRouter{
+		trace: fn(h.trace),
+		debug: fn(h.debug),
+		info:  fn(h.info),
+		warn:  fn(h.warn),
+		error: fn(h.error),
+	}
+}
+
+// handlerProxy luôn chuyển tiếp tới handler gốc hiện tại, để logger tạo
+// trước khi Close vẫn ghi đúng chỗ sau khi handler gốc bị thay.
+// ops là chuỗi WithAttrs/WithGroup được áp dụng lại lên handler gốc; kết quả
+// được giữ trong cache và chỉ dựng lại khi handler gốc đổi.
+type handlerProxy struct {
+	ops   []func(slog.Handler) slog.Handler
+	cache atomic.Pointer[derivedHandler]
+}
+
+// derivedHandler là handler đã áp ops lên handler gốc root
+type derivedHandler struct {
+	root *levelRouter
+	h    slog.Handler
+}
+
+func (p *handlerProxy) current() slog.Handler {
+	root := rootHandler.Load()
+	if len(p.ops) == 0 {
+		return root
+	}
+	if d := p.cache.Load(); d != nil && d.root == root {
+		return d.h
+	}
+	var h slog.Handler = root
+	for _, op := range p.ops {
+		h = op(h)
+	}
+	p.cache.Store(&derivedHandler{root: root, h: h})
+	return h
+}
+
+func (p *handlerProxy) with(op func(slog.Handler) slog.Handler) *handlerProxy {
+	return &handlerProxy{ops: append(p.ops[:len(p.ops):len(p.ops)], op)}
+}
+
+func (p *handlerProxy) Enabled(_ context.Context, l slog.Level) bool {
+	return maybeEnabled(l)
+}
+
+func (p *handlerProxy) Handle(ctx context.Context, r slog.Record) error {
+	return p.current().Handle(ctx, r)
+}
+
+func (p *handlerProxy) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return p.with(func(h slog.Handler) slog.Handler { return h.WithAttrs(attrs) })
+}
+
+func (p *handlerProxy) WithGroup(name string) slog.Handler {
+	return p.with(func(h slog.Handler) slog.Handler { return h.WithGroup(name) })
+}
+
+// compatWriter biến mỗi dòng của *log.Logger kiểu cũ thành một bản ghi slog
+type compatWriter struct {
+	level slog.Level
+}
+
+func (w compatWriter) Write(p []byte) (int, error) {
+	if p == nil || !maybeEnabled(w.level) {
+		return len(p), nil
+	}
+	// Bỏ qua Callers, Write, log.(*Logger).output, log.(*Logger).Printf
+	// để lấy đúng dòng code đã gọi logger.XxxLogger.Printf
+	var pcs [1]uintptr
+	runtime.Callers(4, pcs[:])
+	r := slog.NewRecord(time.Now(), w.level, strings.TrimSuffix(string(p), "\n"), pcs[0])
+	return len(p), rootHandler.Load().Handle(context.Background(), r)
+}
+
+func newCompatLogger(level slog.Level) *log.Logger {
+	return log.New(compatWriter{level: level}, "", 0)
+}
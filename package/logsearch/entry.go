@@ -0,0 +1,40 @@
+// Package logsearch đọc, gộp và lọc các file log theo mức độ trong log/.
+// Hiểu cả định dạng cũ của log.Logger ("INFO: 2025/11/07 16:58:42 main.go:16: msg")
+// lẫn định dạng slog text (key=value) và JSON.
+package logsearch
+
+import (
+	"log/slog"
+	"time"
+)
+
+// DefaultFiles là các file log theo mức độ, mỗi mức một file
+var DefaultFiles = []string{"trace.log", "debug.log", "info.log", "warn.log", "error.log"}
+
+// Attr là một cặp key=value ngoài time/level/source/msg
+type Attr struct {
+	Key   string
+	Value string
+}
+
+// Entry là một bản ghi log đã phân tích. Dòng không phân tích được (ví dụ
+// stack trace nhiều dòng) được nối vào Message của bản ghi đứng trước.
+type Entry struct {
+	Time    time.Time
+	Level   slog.Level
+	Source  string
+	Message string
+	Attrs   []Attr
+	File    string // tên file chứa bản ghi, ví dụ "info.log"
+	Raw     string
+}
+
+// Get trả về giá trị của key, "" nếu không có
+func (e *Entry) Get(key string) string {
+	for _, a := range e.Attrs {
+		if a.Key == key {
+			return a.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,52 @@
+package logsearch
+
+import (
+	"log/slog"
+	"path"
+	"strings"
+	"time"
+)
+
+// Filter chọn bản ghi cần hiện. Trường rỗng nghĩa là không lọc theo trường
+// đó; riêng MinLevel có giá trị 0 là INFO nên muốn lấy hết thì đặt LevelTrace.
+type Filter struct {
+	MinLevel  slog.Level
+	Since     time.Time // bao gồm
+	Until     time.Time // không bao gồm
+	Path      string    // khớp nguyên văn hoặc theo mẫu của path.Match, ví dụ /user/*
+	RequestID string
+	Text      string // tìm không phân biệt hoa thường trong cả dòng
+}
+
+// Match báo e có thỏa f hay không
+func (f Filter) Match(e *Entry) bool {
+	if e.Level < f.MinLevel {
+		return false
+	}
+	if !f.Since.IsZero() && e.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !e.Time.Before(f.Until) {
+		return false
+	}
+	if f.RequestID != "" && e.Get("request_id") != f.RequestID {
+		return false
+	}
+	if f.Path != "" && !matchPath(f.Path, e.Get("path")) {
+		return false
+	}
+	if f.Text != "" && !strings.Contains(strings.ToLower(e.Raw), strings.ToLower(f.Text)) {
+		return false
+	}
+	return true
+}
+
+func matchPath(pattern, p string) bool {
+	if p == "" {
+		return false
+	}
+	if ok, err := path.Match(pattern, p); err == nil && ok {
+		return true
+	}
+	return pattern == p
+}
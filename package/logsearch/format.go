@@ -0,0 +1,110 @@
+package logsearch
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+
+	"vadilatorgolang/package/logger"
+)
+
+// Mã màu ANSI cho từng mức độ
+const (
+	colorReset = "\x1b[0m"
+	colorDim   = "\x1b[2m"
+)
+
+func levelColor(l slog.Level) string {
+	switch {
+	case l < logger.LevelDebug:
+		return "\x1b[90m"
+	case l < logger.LevelInfo:
+		return "\x1b[36m"
+	case l < logger.LevelWarn:
+		return "\x1b[32m"
+	case l < logger.LevelError:
+		return "\x1b[33m"
+	}
+	return "\x1b[31m"
+}
+
+// TablePrinter in mỗi bản ghi thành một dòng cột cố định:
+// thời gian, mức độ, source, message rồi các field key=value.
+type TablePrinter struct {
+	w     *bufio.Writer
+	Color bool
+}
+
+func NewTablePrinter(w io.Writer, color bool) *TablePrinter {
+	return &TablePrinter{w: bufio.NewWriter(w), Color: color}
+}
+
+func (p *TablePrinter) Print(e *Entry) error {
+	ts := "-"
+	if !e.Time.IsZero() {
+		ts = e.Time.Local().Format("2006-01-02 15:04:05.000")
+	}
+	level := fmt.Sprintf("%-5s", logger.LevelName(e.Level))
+	if p.Color {
+		level = levelColor(e.Level) + level + colorReset
+	}
+	fmt.Fprintf(p.w, "%-23s %s %-20s %s", ts, level, e.Source, e.Message)
+	for _, a := range e.Attrs {
+		if p.Color {
+			fmt.Fprintf(p.w, " %s%s=%s%s", colorDim, a.Key, colorReset, quote(a.Value))
+		} else {
+			fmt.Fprintf(p.w, " %s=%s", a.Key, quote(a.Value))
+		}
+	}
+	p.w.WriteByte('\n')
+	return p.w.Flush()
+}
+
+func quote(s string) string {
+	if s == "" || strings.ContainsAny(s, " \t\n\"=") {
+		return fmt.Sprintf("%q", s)
+	}
+	return s
+}
+
+// JSONPrinter in mỗi bản ghi thành một dòng JSON đã chuẩn hóa, bất kể
+// định dạng gốc của dòng log
+type JSONPrinter struct {
+	w *bufio.Writer
+}
+
+func NewJSONPrinter(w io.Writer) *JSONPrinter {
+	return &JSONPrinter{w: bufio.NewWriter(w)}
+}
+
+func (p *JSONPrinter) Print(e *Entry) error {
+	p.w.WriteString(`{"time":`)
+	if e.Time.IsZero() {
+		p.w.WriteString("null")
+	} else {
+		writeJSONString(p.w, e.Time.Format(time.RFC3339Nano))
+	}
+	fields := []Attr{
+		{"level", logger.LevelName(e.Level)},
+		{"file", e.File},
+		{"source", e.Source},
+		{"msg", e.Message},
+	}
+	for _, a := range append(fields, e.Attrs...) {
+		p.w.WriteByte(',')
+		writeJSONString(p.w, a.Key)
+		p.w.WriteByte(':')
+		writeJSONString(p.w, a.Value)
+	}
+	p.w.WriteString("}\n")
+	return p.w.Flush()
+}
+
+func writeJSONString(w *bufio.Writer, s string) {
+	b, _ := json.Marshal(s)
+	w.Write(b)
+}
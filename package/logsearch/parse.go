@@ -0,0 +1,173 @@
+package logsearch
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"vadilatorgolang/package/logger"
+)
+
+// legacyLine khớp định dạng của log.Logger với prefix mức độ và
+// log.Ldate|log.Ltime|log.Lshortfile: "INFO: 2025/11/07 16:58:42 main.go:16: msg"
+var legacyLine = regexp.MustCompile(`^([A-Z]+): (\d{4}/\d{2}/\d{2} \d{2}:\d{2}:\d{2}(?:\.\d+)?) (?:([^\s:]+:\d+): )?(.*)$`)
+
+// Parse phân tích một dòng log. ok = false nghĩa là dòng không mở đầu một
+// bản ghi mới (dòng nối tiếp hoặc rác).
+func Parse(line string) (e Entry, ok bool) {
+	line = strings.TrimRight(line, "\r\n")
+	switch {
+	case strings.HasPrefix(line, "{"):
+		e, ok = parseJSON(line)
+	case strings.HasPrefix(line, "time="):
+		e, ok = parseText(line)
+	default:
+		e, ok = parseLegacy(line)
+	}
+	e.Raw = line
+	return e, ok
+}
+
+func parseLegacy(line string) (Entry, bool) {
+	m := legacyLine.FindStringSubmatch(line)
+	if m == nil {
+		return Entry{}, false
+	}
+	level, err := logger.ParseLevel(m[1])
+	if err != nil {
+		return Entry{}, false
+	}
+	t, err := time.ParseInLocation("2006/01/02 15:04:05", m[2], time.Local)
+	if err != nil {
+		return Entry{}, false
+	}
+	return Entry{Time: t, Level: level, Source: m[3], Message: m[4]}, true
+}
+
+// parseText đọc định dạng key=value của slog.TextHandler
+func parseText(line string) (Entry, bool) {
+	var e Entry
+	hasTime := false
+	for line != "" {
+		key, rest, found := strings.Cut(line, "=")
+		if !found || key == "" {
+			break
+		}
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			q, err := strconv.QuotedPrefix(rest)
+			if err != nil {
+				return Entry{}, false
+			}
+			value, _ = strconv.Unquote(q)
+			rest = rest[len(q):]
+		} else {
+			value, rest, _ = strings.Cut(rest, " ")
+			rest = " " + rest
+		}
+		line = strings.TrimLeft(rest, " ")
+
+		if !e.set(key, value) {
+			e.Attrs = append(e.Attrs, Attr{Key: key, Value: value})
+		}
+		if key == slog.TimeKey {
+			hasTime = !e.Time.IsZero()
+		}
+	}
+	return e, hasTime
+}
+
+func parseJSON(line string) (Entry, bool) {
+	dec := json.NewDecoder(strings.NewReader(line))
+	dec.UseNumber()
+	var m map[string]any
+	if err := dec.Decode(&m); err != nil {
+		return Entry{}, false
+	}
+
+	var e Entry
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	// map làm mất thứ tự; giữ các field ngữ cảnh quen thuộc lên trước, còn
+	// lại theo tên để kết quả ổn định giữa các lần chạy
+	sort.Slice(keys, func(i, j int) bool {
+		if ri, rj := keyRank(keys[i]), keyRank(keys[j]); ri != rj {
+			return ri < rj
+		}
+		return keys[i] < keys[j]
+	})
+	for _, k := range keys {
+		flatten(&e, k, m[k])
+	}
+	return e, !e.Time.IsZero()
+}
+
+func keyRank(k string) int {
+	switch k {
+	case "request_id":
+		return 0
+	case "method":
+		return 1
+	case "path":
+		return 2
+	case "user_id":
+		return 3
+	}
+	return 4
+}
+
+// flatten đưa object lồng nhau (group của slog) về dạng "group.key"
+func flatten(e *Entry, key string, v any) {
+	switch v := v.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			flatten(e, key+"."+k, v[k])
+		}
+	case string:
+		if !e.set(key, v) {
+			e.Attrs = append(e.Attrs, Attr{Key: key, Value: v})
+		}
+	case nil:
+		e.Attrs = append(e.Attrs, Attr{Key: key, Value: "null"})
+	default:
+		var buf bytes.Buffer
+		json.NewEncoder(&buf).Encode(v)
+		e.Attrs = append(e.Attrs, Attr{Key: key, Value: strings.TrimSpace(buf.String())})
+	}
+}
+
+// set gán các field chuẩn của slog; trả về false nếu key là field thường
+func (e *Entry) set(key, value string) bool {
+	switch key {
+	case slog.TimeKey:
+		t, err := time.Parse(time.RFC3339Nano, value)
+		if err == nil {
+			e.Time = t
+		}
+	case slog.LevelKey:
+		l, err := logger.ParseLevel(value)
+		if err != nil {
+			return false
+		}
+		e.Level = l
+	case slog.SourceKey:
+		e.Source = value
+	case slog.MessageKey:
+		e.Message = value
+	default:
+		return false
+	}
+	return true
+}
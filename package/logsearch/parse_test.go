@@ -0,0 +1,112 @@
+package logsearch
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"vadilatorgolang/package/logger"
+)
+
+func TestParse(t *testing.T) {
+	ts := time.Date(2026, 10, 18, 8, 0, 0, 123000000, time.UTC)
+	tests := []struct {
+		name string
+		line string
+		ok   bool
+		want Entry
+	}{
+		{
+			"text", `time=2026-10-18T08:00:00.123Z level=WARN source=handler.go:42 msg="Lỗi xử lý request" request_id=abc code=user_not_found error="not found: id=5"`,
+			true, Entry{Time: ts, Level: slog.LevelWarn, Source: "handler.go:42", Message: "Lỗi xử lý request",
+				Attrs: []Attr{{"request_id", "abc"}, {"code", "user_not_found"}, {"error", "not found: id=5"}}},
+		},
+		{
+			"json có group", `{"time":"2026-10-18T08:00:00.123Z","level":"TRACE","msg":"x","user_id":5,"request_id":"abc","http":{"status":200,"path":"/user"},"err":null}`,
+			true, Entry{Time: ts, Level: logger.LevelTrace, Message: "x",
+				Attrs: []Attr{{"request_id", "abc"}, {"user_id", "5"}, {"err", "null"}, {"http.path", "/user"}, {"http.status", "200"}}},
+		},
+		{
+			"legacy", "ERROR: 2026/10/18 08:00:00 main.go:16: không kết nối được",
+			true, Entry{Time: time.Date(2026, 10, 18, 8, 0, 0, 0, time.Local), Level: slog.LevelError, Source: "main.go:16", Message: "không kết nối được"},
+		},
+		{"dòng nối tiếp", "\tgoroutine 1 [running]:", false, Entry{}},
+		{"text thiếu time", `level=INFO msg=x`, false, Entry{}},
+		{"text ngoặc kép hỏng", `time=2026-10-18T08:00:00Z msg="x`, false, Entry{}},
+		{"json thiếu time", `{"level":"INFO","msg":"x"}`, false, Entry{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, ok := Parse(tt.line + "\n")
+			if ok != tt.ok {
+				t.Fatalf("ok = %v, muốn %v", ok, tt.ok)
+			}
+			if !ok {
+				return
+			}
+			tt.want.Raw = tt.line
+			if !e.Time.Equal(tt.want.Time) {
+				t.Errorf("Time = %v, muốn %v", e.Time, tt.want.Time)
+			}
+			e.Time, tt.want.Time = time.Time{}, time.Time{}
+			if !reflect.DeepEqual(e, tt.want) {
+				t.Errorf("Parse =\n%+v\nmuốn\n%+v", e, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchMerge(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, lines ...string) {
+		var b []byte
+		for _, l := range lines {
+			b = append(b, l+"\n"...)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), b, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("info.log",
+		`time=2026-10-18T08:00:01Z level=INFO msg=a path=/user`,
+		`time=2026-10-18T08:00:03Z level=INFO msg=c path=/user/5`,
+		`time=2026-10-18T08:00:05Z level=INFO msg=e path=/audit`,
+	)
+	write("error.log",
+		`time=2026-10-18T08:00:02Z level=ERROR msg=b path=/user/5`,
+		"\tstack dòng 1",
+		`time=2026-10-18T08:00:04Z level=ERROR msg=d path=/user/7`,
+	)
+
+	run := func(opts Options) []string {
+		t.Helper()
+		opts.Dir = dir
+		var got []string
+		err := Search(context.Background(), opts, func(e *Entry) error {
+			got = append(got, e.Message)
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return got
+	}
+
+	if got := run(Options{}); !reflect.DeepEqual(got, []string{"a", "b\n\tstack dòng 1", "c", "d", "e"}) {
+		t.Errorf("gộp theo thời gian = %q", got)
+	}
+	if got := run(Options{Filter: Filter{MinLevel: slog.LevelError}}); !reflect.DeepEqual(got, []string{"b\n\tstack dòng 1", "d"}) {
+		t.Errorf("lọc mức = %q", got)
+	}
+	if got := run(Options{Filter: Filter{Path: "/user/*"}, Tail: 2}); !reflect.DeepEqual(got, []string{"c", "d"}) {
+		t.Errorf("lọc path + tail = %q", got)
+	}
+	until := time.Date(2026, 10, 18, 8, 0, 3, 0, time.UTC)
+	if got := run(Options{Filter: Filter{Until: until}}); !reflect.DeepEqual(got, []string{"a", "b\n\tstack dòng 1"}) {
+		t.Errorf("lọc until = %q", got)
+	}
+}
@@ -0,0 +1,138 @@
+package logsearch
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"vadilatorgolang/package/logger"
+)
+
+// fileReader đọc lần lượt bản ghi của một file log. Ở chế độ follow nó giữ
+// lại dòng chưa kết thúc và tự mở lại file khi file bị xoay vòng hoặc cắt ngắn.
+type fileReader struct {
+	path    string
+	name    string
+	follow  bool
+	f       *os.File
+	r       *bufio.Reader
+	off     int64
+	partial string
+	pending *Entry
+	level   slog.Level // suy từ tên file, dùng cho dòng đầu không phân tích được
+}
+
+func newFileReader(path string, follow bool) *fileReader {
+	fr := &fileReader{path: path, name: filepath.Base(path), follow: follow}
+	if l, err := logger.ParseLevel(strings.TrimSuffix(fr.name, filepath.Ext(fr.name))); err == nil {
+		fr.level = l
+	}
+	return fr
+}
+
+// open mở file nếu chưa mở; file chưa tồn tại không phải là lỗi
+func (fr *fileReader) open() (bool, error) {
+	if fr.f != nil {
+		return true, nil
+	}
+	f, err := os.Open(fr.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	fr.f, fr.r, fr.off, fr.partial = f, bufio.NewReaderSize(f, 64<<10), 0, ""
+	return true, nil
+}
+
+// next trả về bản ghi kế tiếp, hoặc nil khi tạm hết dữ liệu
+func (fr *fileReader) next() (*Entry, error) {
+	if ok, err := fr.open(); !ok {
+		return nil, err
+	}
+	for {
+		line, err := fr.r.ReadString('\n')
+		fr.off += int64(len(line))
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+		if errors.Is(err, io.EOF) {
+			if fr.follow && line != "" {
+				// dòng đang được ghi dở, chờ lần đọc sau
+				fr.partial += line
+				line = ""
+			}
+			if line == "" {
+				e := fr.pending
+				fr.pending = nil
+				if e == nil && fr.follow {
+					if err := fr.checkRotated(); err != nil {
+						return nil, err
+					}
+				}
+				return e, nil
+			}
+		}
+		line, fr.partial = fr.partial+line, ""
+
+		e, ok := Parse(line)
+		if !ok {
+			if strings.TrimSpace(e.Raw) == "" {
+				continue
+			}
+			if fr.pending != nil {
+				fr.pending.Message += "\n" + e.Raw
+				fr.pending.Raw += "\n" + e.Raw
+				continue
+			}
+			e = Entry{Level: fr.level, Message: e.Raw, Raw: e.Raw}
+		}
+		e.File = fr.name
+		prev := fr.pending
+		fr.pending = &e
+		if prev != nil {
+			return prev, nil
+		}
+	}
+}
+
+// checkRotated mở lại file khi đường dẫn đã trỏ sang file khác (xoay vòng)
+// hoặc đọc lại từ đầu khi file bị cắt ngắn
+func (fr *fileReader) checkRotated() error {
+	st, err := os.Stat(fr.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	cur, err := fr.f.Stat()
+	if err != nil {
+		return err
+	}
+	switch {
+	case !os.SameFile(st, cur):
+		fr.close()
+		_, err = fr.open()
+		return err
+	case st.Size() < fr.off:
+		if _, err := fr.f.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		fr.r.Reset(fr.f)
+		fr.off, fr.partial = 0, ""
+	}
+	return nil
+}
+
+func (fr *fileReader) close() {
+	if fr.f != nil {
+		fr.f.Close()
+		fr.f = nil
+	}
+}
@@ -0,0 +1,140 @@
+package logsearch
+
+import (
+	"context"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// DefaultPollInterval là chu kỳ kiểm tra dữ liệu mới khi follow
+const DefaultPollInterval = 500 * time.Millisecond
+
+// Options cấu hình một lần tìm kiếm
+type Options struct {
+	Dir          string
+	Files        []string // mặc định DefaultFiles
+	Filter       Filter
+	Tail         int  // chỉ hiện N bản ghi khớp cuối cùng của phần đã có (0 = tất cả)
+	Follow       bool // sau khi đọc hết, tiếp tục chờ bản ghi mới tới khi ctx bị hủy
+	PollInterval time.Duration
+}
+
+// Search gộp các file log theo thứ tự thời gian và gọi emit cho mỗi bản ghi
+// khớp Filter. Mỗi file vốn đã theo thứ tự thời gian nên chỉ cần trộn k đường.
+func Search(ctx context.Context, opts Options, emit func(*Entry) error) error {
+	files := opts.Files
+	if len(files) == 0 {
+		files = DefaultFiles
+	}
+	readers := make([]*fileReader, len(files))
+	for i, name := range files {
+		readers[i] = newFileReader(filepath.Join(opts.Dir, name), opts.Follow)
+	}
+	defer func() {
+		for _, fr := range readers {
+			fr.close()
+		}
+	}()
+
+	out := emit
+	var tail []*Entry
+	if opts.Tail > 0 {
+		out = func(e *Entry) error {
+			if len(tail) == opts.Tail {
+				tail = tail[1:]
+			}
+			tail = append(tail, e)
+			return nil
+		}
+	}
+
+	if err := merge(ctx, readers, opts.Filter, !opts.Follow, out); err != nil {
+		return err
+	}
+	for _, e := range tail {
+		if err := emit(e); err != nil {
+			return err
+		}
+	}
+	if !opts.Follow {
+		return nil
+	}
+
+	interval := opts.PollInterval
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		// Bản ghi mới của các file khác nhau tới cùng lúc, sắp lại theo thời gian
+		var batch []*Entry
+		for _, fr := range readers {
+			for {
+				e, err := fr.next()
+				if err != nil {
+					return err
+				}
+				if e == nil {
+					break
+				}
+				if opts.Filter.Match(e) {
+					batch = append(batch, e)
+				}
+			}
+		}
+		sort.SliceStable(batch, func(i, j int) bool { return batch[i].Time.Before(batch[j].Time) })
+		for _, e := range batch {
+			if err := emit(e); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// merge trộn phần đã có của các file. stopAtUntil cho phép dừng sớm khi đã
+// vượt quá Filter.Until (không dùng khi follow vì file vẫn còn được ghi tiếp).
+func merge(ctx context.Context, readers []*fileReader, f Filter, stopAtUntil bool, emit func(*Entry) error) error {
+	heads := make([]*Entry, len(readers))
+	for i, fr := range readers {
+		e, err := fr.next()
+		if err != nil {
+			return err
+		}
+		heads[i] = e
+	}
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil
+		}
+		min := -1
+		for i, e := range heads {
+			if e != nil && (min < 0 || e.Time.Before(heads[min].Time)) {
+				min = i
+			}
+		}
+		if min < 0 {
+			return nil
+		}
+		e := heads[min]
+		if stopAtUntil && !f.Until.IsZero() && !e.Time.Before(f.Until) {
+			return nil
+		}
+		if f.Match(e) {
+			if err := emit(e); err != nil {
+				return err
+			}
+		}
+		next, err := readers[min].next()
+		if err != nil {
+			return err
+		}
+		heads[min] = next
+	}
+}
@@ -0,0 +1,33 @@
+package metrics
+
+import "database/sql"
+
+// DBStatsCollector xuất sql.DBStats của một pool kết nối, gắn label db
+type DBStatsCollector struct {
+	db     *sql.DB
+	labels []Label
+}
+
+// NewDBStatsCollector tạo collector cho db; name là giá trị label db
+// (thường là tên database) để phân biệt khi có nhiều pool
+func NewDBStatsCollector(db *sql.DB, name string) *DBStatsCollector {
+	return &DBStatsCollector{db: db, labels: []Label{{"db", name}}}
+}
+
+func (c *DBStatsCollector) Collect(e *Encoder) {
+	s := c.db.Stats()
+	metric := func(name, help string, typ Type, v float64) {
+		e.Family(name, help, typ)
+		e.Sample(name, c.labels, v)
+	}
+
+	metric("go_sql_max_open_connections", "Số kết nối mở tối đa của pool (0 = không giới hạn).", TypeGauge, float64(s.MaxOpenConnections))
+	metric("go_sql_open_connections", "Số kết nối đang mở, gồm cả đang dùng và rảnh.", TypeGauge, float64(s.OpenConnections))
+	metric("go_sql_in_use_connections", "Số kết nối đang được dùng.", TypeGauge, float64(s.InUse))
+	metric("go_sql_idle_connections", "Số kết nối rảnh.", TypeGauge, float64(s.Idle))
+	metric("go_sql_wait_count_total", "Tổng số lần phải chờ kết nối vì pool đã đầy.", TypeCounter, float64(s.WaitCount))
+	metric("go_sql_wait_duration_seconds_total", "Tổng thời gian chờ kết nối.", TypeCounter, s.WaitDuration.Seconds())
+	metric("go_sql_max_idle_closed_total", "Số kết nối bị đóng do vượt MaxIdleConns.", TypeCounter, float64(s.MaxIdleClosed))
+	metric("go_sql_max_idle_time_closed_total", "Số kết nối bị đóng do rảnh quá ConnMaxIdleTime.", TypeCounter, float64(s.MaxIdleTimeClosed))
+	metric("go_sql_max_lifetime_closed_total", "Số kết nối bị đóng do quá ConnMaxLifetime.", TypeCounter, float64(s.MaxLifetimeClosed))
+}
@@ -0,0 +1,79 @@
+package metrics
+
+import (
+	"bufio"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Label là một cặp tên=giá trị của sample
+type Label struct {
+	Name, Value string
+}
+
+// Encoder ghi metric theo định dạng text của Prometheus. Mỗi family gồm dòng
+// # HELP, # TYPE rồi tới các sample.
+type Encoder struct {
+	w *bufio.Writer
+}
+
+// Family ghi phần đầu của một metric family
+func (e *Encoder) Family(name, help string, typ Type) {
+	e.w.WriteString("# HELP ")
+	e.w.WriteString(name)
+	e.w.WriteByte(' ')
+	e.w.WriteString(helpEscaper.Replace(help))
+	e.w.WriteString("\n# TYPE ")
+	e.w.WriteString(name)
+	e.w.WriteByte(' ')
+	e.w.WriteString(string(typ))
+	e.w.WriteByte('\n')
+}
+
+// Sample ghi một dòng giá trị
+func (e *Encoder) Sample(name string, labels []Label, value float64) {
+	e.w.WriteString(name)
+	if len(labels) > 0 {
+		e.w.WriteByte('{')
+		for i, l := range labels {
+			if i > 0 {
+				e.w.WriteByte(',')
+			}
+			e.w.WriteString(l.Name)
+			e.w.WriteString(`="`)
+			e.w.WriteString(labelEscaper.Replace(l.Value))
+			e.w.WriteByte('"')
+		}
+		e.w.WriteByte('}')
+	}
+	e.w.WriteByte(' ')
+	e.w.WriteString(formatFloat(value))
+	e.w.WriteByte('\n')
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// labelPairs ghép tên label với giá trị, thêm extra (ví dụ le của histogram) ở cuối
+func labelPairs(names, values []string, extra ...Label) []Label {
+	labels := make([]Label, 0, len(names)+len(extra))
+	for i, n := range names {
+		labels = append(labels, Label{n, values[i]})
+	}
+	return append(labels, extra...)
+}
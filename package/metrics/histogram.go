@@ -0,0 +1,73 @@
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync/atomic"
+)
+
+// DefBuckets là các bucket mặc định (giây), hợp với thời gian xử lý request
+var DefBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Histogram đếm số quan sát theo bucket cùng tổng và số lượng
+type Histogram struct {
+	upper  []float64
+	counts []atomic.Uint64 // không cộng dồn; phần tử cuối là bucket +Inf
+	sum    atomic.Uint64
+}
+
+// Observe ghi nhận một giá trị
+func (h *Histogram) Observe(v float64) {
+	// bucket đầu tiên có cận trên >= v; không có thì rơi vào +Inf
+	i := sort.SearchFloat64s(h.upper, v)
+	h.counts[i].Add(1)
+	addFloat(&h.sum, v)
+}
+
+// HistogramVec là histogram có label, mọi histogram con dùng chung bucket
+type HistogramVec struct {
+	vec[Histogram]
+	upper []float64
+}
+
+func newHistogramVec(name, help string, buckets []float64, labels []string) *HistogramVec {
+	if buckets == nil {
+		buckets = DefBuckets
+	}
+	if !sort.Float64sAreSorted(buckets) {
+		panic(fmt.Sprintf("metrics: bucket của %s phải tăng dần", name))
+	}
+	// +Inf luôn có sẵn nên bỏ nếu người gọi tự thêm
+	if n := len(buckets); n > 0 && math.IsInf(buckets[n-1], 1) {
+		buckets = buckets[:n-1]
+	}
+	upper := append([]float64(nil), buckets...)
+
+	v := &HistogramVec{vec: newVec[Histogram](name, help, labels), upper: upper}
+	v.new = func() *Histogram {
+		return &Histogram{upper: upper, counts: make([]atomic.Uint64, len(upper)+1)}
+	}
+	return v
+}
+
+// With trả về histogram ứng với giá trị label (theo thứ tự lúc đăng ký)
+func (v *HistogramVec) With(values ...string) *Histogram { return v.with(values) }
+
+// Collect ghi các sample _bucket (cộng dồn), _sum và _count. _count lấy
+// bằng bucket +Inf để hai giá trị luôn khớp nhau.
+func (v *HistogramVec) Collect(e *Encoder) {
+	e.Family(v.name, v.help, TypeHistogram)
+	for _, c := range v.sorted() {
+		h := c.metric
+		var cum uint64
+		for i, le := range h.upper {
+			cum += h.counts[i].Load()
+			e.Sample(v.name+"_bucket", labelPairs(v.labels, c.values, Label{"le", formatFloat(le)}), float64(cum))
+		}
+		cum += h.counts[len(h.upper)].Load()
+		e.Sample(v.name+"_bucket", labelPairs(v.labels, c.values, Label{"le", "+Inf"}), float64(cum))
+		e.Sample(v.name+"_sum", labelPairs(v.labels, c.values), math.Float64frombits(h.sum.Load()))
+		e.Sample(v.name+"_count", labelPairs(v.labels, c.values), float64(cum))
+	}
+}
@@ -0,0 +1,174 @@
+// Package metrics là bộ đếm counter/gauge/histogram tối giản, xuất ra theo
+// định dạng text của Prometheus (text/plain; version=0.0.4) để scrape qua /metrics.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Type là loại metric ghi trong dòng # TYPE
+type Type string
+
+const (
+	TypeCounter   Type = "counter"
+	TypeGauge     Type = "gauge"
+	TypeHistogram Type = "histogram"
+)
+
+// Collector ghi các metric family của nó mỗi lần được scrape
+type Collector interface {
+	Collect(e *Encoder)
+}
+
+var (
+	metricNameRe = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+	labelNameRe  = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+)
+
+// Registry giữ các collector theo thứ tự đăng ký
+type Registry struct {
+	mu         sync.Mutex
+	collectors []Collector
+	names      map[string]bool
+}
+
+// NewRegistry tạo registry rỗng
+func NewRegistry() *Registry {
+	return &Registry{names: map[string]bool{}}
+}
+
+// Default là registry dùng chung của tiến trình, có sẵn số liệu Go runtime
+var Default = func() *Registry {
+	r := NewRegistry()
+	r.Register(NewRuntimeCollector())
+	return r
+}()
+
+// Register thêm collector tự viết (ví dụ số liệu lấy lúc scrape)
+func (r *Registry) Register(c Collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.collectors = append(r.collectors, c)
+}
+
+// claim giữ tên metric; tên sai quy tắc hoặc trùng là lỗi lập trình nên panic
+func (r *Registry) claim(name string, labels []string) {
+	if !metricNameRe.MatchString(name) {
+		panic(fmt.Sprintf("metrics: tên metric không hợp lệ %q", name))
+	}
+	for _, l := range labels {
+		if !labelNameRe.MatchString(l) || strings.HasPrefix(l, "__") {
+			panic(fmt.Sprintf("metrics: tên label không hợp lệ %q của %s", l, name))
+		}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.names[name] {
+		panic(fmt.Sprintf("metrics: metric %q đã được đăng ký", name))
+	}
+	r.names[name] = true
+}
+
+// NewCounterVec đăng ký counter với các label cho trước
+func (r *Registry) NewCounterVec(name, help string, labels ...string) *CounterVec {
+	r.claim(name, labels)
+	v := &CounterVec{newVec[Counter](name, help, labels)}
+	r.Register(v)
+	return v
+}
+
+// NewGaugeVec đăng ký gauge với các label cho trước
+func (r *Registry) NewGaugeVec(name, help string, labels ...string) *GaugeVec {
+	r.claim(name, labels)
+	v := &GaugeVec{newVec[Gauge](name, help, labels)}
+	r.Register(v)
+	return v
+}
+
+// NewHistogramVec đăng ký histogram; buckets là các cận trên tăng dần
+// (nil = DefBuckets), bucket +Inf được thêm tự động
+func (r *Registry) NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	for _, l := range labels {
+		if l == "le" {
+			panic(fmt.Sprintf("metrics: histogram %s không được dùng label \"le\"", name))
+		}
+	}
+	r.claim(name, labels)
+	v := newHistogramVec(name, help, buckets, labels)
+	r.Register(v)
+	return v
+}
+
+// NewGaugeFunc đăng ký gauge không label có giá trị lấy từ fn lúc scrape
+func (r *Registry) NewGaugeFunc(name, help string, fn func() float64) {
+	r.claim(name, nil)
+	r.Register(&funcCollector{name: name, help: help, typ: TypeGauge, fn: fn})
+}
+
+// NewCounterFunc giống NewGaugeFunc cho giá trị chỉ tăng
+func (r *Registry) NewCounterFunc(name, help string, fn func() float64) {
+	r.claim(name, nil)
+	r.Register(&funcCollector{name: name, help: help, typ: TypeCounter, fn: fn})
+}
+
+// WriteText ghi toàn bộ metric ra w theo định dạng text của Prometheus
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mu.Lock()
+	collectors := append([]Collector(nil), r.collectors...)
+	r.mu.Unlock()
+
+	e := &Encoder{w: bufio.NewWriter(w)}
+	for _, c := range collectors {
+		c.Collect(e)
+	}
+	return e.w.Flush()
+}
+
+// Handler phục vụ endpoint scrape. Lỗi ghi chỉ xảy ra khi client đã ngắt
+// kết nối nên được bỏ qua (package này không phụ thuộc logger để validator
+// và config dùng được).
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_ = r.WriteText(w)
+	})
+}
+
+// Các hàm dưới đây dùng Default
+
+func NewCounterVec(name, help string, labels ...string) *CounterVec {
+	return Default.NewCounterVec(name, help, labels...)
+}
+
+func NewGaugeVec(name, help string, labels ...string) *GaugeVec {
+	return Default.NewGaugeVec(name, help, labels...)
+}
+
+func NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	return Default.NewHistogramVec(name, help, buckets, labels...)
+}
+
+func NewGaugeFunc(name, help string, fn func() float64) { Default.NewGaugeFunc(name, help, fn) }
+
+func NewCounterFunc(name, help string, fn func() float64) { Default.NewCounterFunc(name, help, fn) }
+
+func Register(c Collector) { Default.Register(c) }
+
+func Handler() http.Handler { return Default.Handler() }
+
+type funcCollector struct {
+	name, help string
+	typ        Type
+	fn         func() float64
+}
+
+func (c *funcCollector) Collect(e *Encoder) {
+	e.Family(c.name, c.help, c.typ)
+	e.Sample(c.name, nil, c.fn())
+}
@@ -0,0 +1,111 @@
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandlerGolden(t *testing.T) {
+	r := NewRegistry()
+	requests := r.NewCounterVec("http_requests_total", "Tổng số request.\nTheo route", "method", "route")
+	requests.With("GET", "/users").Add(3)
+	requests.With("POST", `/a"b\c`).Inc()
+	inflight := r.NewGaugeVec("http_in_flight", "Request đang xử lý")
+	inflight.With().Set(2)
+	inflight.With().Dec()
+	latency := r.NewHistogramVec("http_duration_seconds", "Thời gian xử lý", []float64{0.1, 0.5, 1}, "route")
+	for _, v := range []float64{0.05, 0.1, 0.3, 0.7, 2} {
+		latency.With("/users").Observe(v)
+	}
+	r.NewGaugeFunc("up", "Tiến trình đang chạy", func() float64 { return 1 })
+
+	w := httptest.NewRecorder()
+	r.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if ct := w.Header().Get("Content-Type"); ct != "text/plain; version=0.0.4; charset=utf-8" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	want := `# HELP http_requests_total Tổng số request.\nTheo route
+# TYPE http_requests_total counter
+http_requests_total{method="GET",route="/users"} 3
+http_requests_total{method="POST",route="/a\"b\\c"} 1
+# HELP http_in_flight Request đang xử lý
+# TYPE http_in_flight gauge
+http_in_flight 1
+# HELP http_duration_seconds Thời gian xử lý
+# TYPE http_duration_seconds histogram
+http_duration_seconds_bucket{route="/users",le="0.1"} 2
+http_duration_seconds_bucket{route="/users",le="0.5"} 3
+http_duration_seconds_bucket{route="/users",le="1"} 4
+http_duration_seconds_bucket{route="/users",le="+Inf"} 5
+http_duration_seconds_sum{route="/users"} 3.15
+http_duration_seconds_count{route="/users"} 5
+# HELP up Tiến trình đang chạy
+# TYPE up gauge
+up 1
+`
+	if got := w.Body.String(); got != want {
+		t.Errorf("output khác golden\n--- nhận ---\n%s--- muốn ---\n%s", got, want)
+	}
+}
+
+func TestHistogramCumulative(t *testing.T) {
+	r := NewRegistry()
+	// +Inf do người gọi thêm bị bỏ để không ghi trùng bucket
+	h := r.NewHistogramVec("h", "h", []float64{1, 2, 3, math.Inf(1)}).With()
+	for _, v := range []float64{0.5, 1, 1.5, 1.5, 3, 10} {
+		h.Observe(v)
+	}
+	var b strings.Builder
+	if err := r.WriteText(&b); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		`h_bucket{le="1"} 2`,
+		`h_bucket{le="2"} 4`,
+		`h_bucket{le="3"} 5`,
+		`h_bucket{le="+Inf"} 6`,
+		`h_sum 17.5`,
+		`h_count 6`,
+	}
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")[2:]
+	if fmt.Sprint(lines) != fmt.Sprint(want) {
+		t.Errorf("samples = %q, muốn %q", lines, want)
+	}
+}
+
+func TestRegisterPanics(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func(r *Registry)
+	}{
+		{"tên trùng", func(r *Registry) {
+			r.NewCounterVec("dup", "a")
+			r.NewGaugeVec("dup", "b")
+		}},
+		{"tên trùng với func", func(r *Registry) {
+			r.NewHistogramVec("dup", "a", nil)
+			r.NewGaugeFunc("dup", "b", func() float64 { return 0 })
+		}},
+		{"tên metric sai", func(r *Registry) { r.NewCounterVec("1bad", "a") }},
+		{"tên label sai", func(r *Registry) { r.NewCounterVec("ok", "a", "bad-label") }},
+		{"label dành riêng", func(r *Registry) { r.NewCounterVec("ok", "a", "__name") }},
+		{"histogram dùng le", func(r *Registry) { r.NewHistogramVec("ok", "a", nil, "le") }},
+		{"bucket không tăng dần", func(r *Registry) { r.NewHistogramVec("ok", "a", []float64{2, 1}) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("không panic")
+				}
+			}()
+			tt.fn(NewRegistry())
+		})
+	}
+}
+
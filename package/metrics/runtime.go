@@ -0,0 +1,53 @@
+package metrics
+
+import (
+	"runtime"
+	"runtime/pprof"
+	"time"
+)
+
+// processStart là thời điểm tiến trình khởi động (xấp xỉ bằng lúc nạp package)
+var processStart = time.Now()
+
+// RuntimeCollector xuất số liệu của Go runtime: goroutine, thread, bộ nhớ, GC
+type RuntimeCollector struct{}
+
+func NewRuntimeCollector() *RuntimeCollector { return &RuntimeCollector{} }
+
+func (RuntimeCollector) Collect(e *Encoder) {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+
+	gauge := func(name, help string, v float64) {
+		e.Family(name, help, TypeGauge)
+		e.Sample(name, nil, v)
+	}
+	counter := func(name, help string, v float64) {
+		e.Family(name, help, TypeCounter)
+		e.Sample(name, nil, v)
+	}
+
+	e.Family("go_info", "Phiên bản Go của binary.", TypeGauge)
+	e.Sample("go_info", []Label{{"version", runtime.Version()}}, 1)
+	gauge("go_goroutines", "Số goroutine đang tồn tại.", float64(runtime.NumGoroutine()))
+	gauge("go_threads", "Số thread hệ điều hành đã tạo.", float64(pprof.Lookup("threadcreate").Count()))
+	gauge("go_gomaxprocs", "Giá trị GOMAXPROCS.", float64(runtime.GOMAXPROCS(0)))
+
+	gauge("go_memstats_heap_alloc_bytes", "Số byte heap đã cấp phát và còn dùng.", float64(ms.HeapAlloc))
+	gauge("go_memstats_heap_inuse_bytes", "Số byte trong các span heap đang dùng.", float64(ms.HeapInuse))
+	gauge("go_memstats_heap_idle_bytes", "Số byte trong các span heap rảnh.", float64(ms.HeapIdle))
+	gauge("go_memstats_heap_objects", "Số object đang có trên heap.", float64(ms.HeapObjects))
+	gauge("go_memstats_stack_inuse_bytes", "Số byte stack đang dùng.", float64(ms.StackInuse))
+	gauge("go_memstats_sys_bytes", "Tổng số byte lấy từ hệ điều hành.", float64(ms.Sys))
+	gauge("go_memstats_next_gc_bytes", "Kích thước heap sẽ kích hoạt lần GC kế tiếp.", float64(ms.NextGC))
+	counter("go_memstats_alloc_bytes_total", "Tổng số byte đã cấp phát trên heap, kể cả đã giải phóng.", float64(ms.TotalAlloc))
+	counter("go_memstats_mallocs_total", "Tổng số lần cấp phát heap.", float64(ms.Mallocs))
+	counter("go_memstats_frees_total", "Tổng số lần giải phóng heap.", float64(ms.Frees))
+
+	counter("go_gc_cycles_total", "Số chu kỳ GC đã hoàn thành.", float64(ms.NumGC))
+	counter("go_gc_pause_seconds_total", "Tổng thời gian dừng chương trình do GC.", float64(ms.PauseTotalNs)/1e9)
+	gauge("go_memstats_last_gc_time_seconds", "Thời điểm GC gần nhất (unix giây).", float64(ms.LastGC)/1e9)
+	gauge("go_memstats_gc_cpu_fraction", "Tỉ lệ CPU dành cho GC từ khi khởi động.", ms.GCCPUFraction)
+
+	gauge("process_start_time_seconds", "Thời điểm tiến trình khởi động (unix giây).", float64(processStart.UnixNano())/1e9)
+}
@@ -0,0 +1,143 @@
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Counter là giá trị chỉ tăng
+type Counter struct {
+	bits atomic.Uint64
+}
+
+func (c *Counter) Inc() { c.Add(1) }
+
+// Add cộng v (phải >= 0)
+func (c *Counter) Add(v float64) {
+	if v < 0 {
+		panic("metrics: counter không được giảm")
+	}
+	addFloat(&c.bits, v)
+}
+
+func (c *Counter) Value() float64 { return math.Float64frombits(c.bits.Load()) }
+
+// Gauge là giá trị tăng giảm tùy ý
+type Gauge struct {
+	bits atomic.Uint64
+}
+
+func (g *Gauge) Set(v float64)  { g.bits.Store(math.Float64bits(v)) }
+func (g *Gauge) Add(v float64)  { addFloat(&g.bits, v) }
+func (g *Gauge) Inc()           { g.Add(1) }
+func (g *Gauge) Dec()           { g.Add(-1) }
+func (g *Gauge) Value() float64 { return math.Float64frombits(g.bits.Load()) }
+
+// addFloat cộng dồn float64 lưu dạng bit bằng compare-and-swap
+func addFloat(bits *atomic.Uint64, v float64) {
+	for {
+		old := bits.Load()
+		if bits.CompareAndSwap(old, math.Float64bits(math.Float64frombits(old)+v)) {
+			return
+		}
+	}
+}
+
+// vec là một metric family: mỗi bộ giá trị label có một metric riêng
+type vec[M any] struct {
+	name, help string
+	labels     []string
+	new        func() *M
+
+	mu       sync.RWMutex
+	children map[string]*child[M]
+}
+
+type child[M any] struct {
+	values []string
+	metric *M
+}
+
+func newVec[M any](name, help string, labels []string) vec[M] {
+	return vec[M]{
+		name:     name,
+		help:     help,
+		labels:   labels,
+		new:      func() *M { return new(M) },
+		children: map[string]*child[M]{},
+	}
+}
+
+// with trả về metric của bộ giá trị label, tạo mới nếu chưa có
+func (v *vec[M]) with(values []string) *M {
+	if len(values) != len(v.labels) {
+		panic(fmt.Sprintf("metrics: %s cần %d giá trị label, nhận %d", v.name, len(v.labels), len(values)))
+	}
+	key := strings.Join(values, "\xff")
+
+	v.mu.RLock()
+	c, ok := v.children[key]
+	v.mu.RUnlock()
+	if ok {
+		return c.metric
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if c, ok := v.children[key]; ok {
+		return c.metric
+	}
+	c = &child[M]{values: append([]string(nil), values...), metric: v.new()}
+	v.children[key] = c
+	return c.metric
+}
+
+// sorted trả về các metric con theo thứ tự label để output ổn định
+func (v *vec[M]) sorted() []*child[M] {
+	v.mu.RLock()
+	keys := make([]string, 0, len(v.children))
+	for k := range v.children {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	out := make([]*child[M], len(keys))
+	for i, k := range keys {
+		out[i] = v.children[k]
+	}
+	v.mu.RUnlock()
+	return out
+}
+
+// CounterVec là counter có label
+type CounterVec struct {
+	vec[Counter]
+}
+
+// With trả về counter ứng với giá trị label (theo thứ tự lúc đăng ký)
+func (v *CounterVec) With(values ...string) *Counter { return v.with(values) }
+
+func (v *CounterVec) Collect(e *Encoder) {
+	e.Family(v.name, v.help, TypeCounter)
+	for _, c := range v.sorted() {
+		e.Sample(v.name, labelPairs(v.labels, c.values), c.metric.Value())
+	}
+}
+
+// GaugeVec là gauge có label
+type GaugeVec struct {
+	vec[Gauge]
+}
+
+// With trả về gauge ứng với giá trị label (theo thứ tự lúc đăng ký)
+func (v *GaugeVec) With(values ...string) *Gauge { return v.with(values) }
+
+func (v *GaugeVec) Collect(e *Encoder) {
+	e.Family(v.name, v.help, TypeGauge)
+	for _, c := range v.sorted() {
+		e.Sample(v.name, labelPairs(v.labels, c.values), c.metric.Value())
+	}
+}
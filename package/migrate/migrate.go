@@ -0,0 +1,293 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// lockName là tên advisory lock của MySQL (GET_LOCK) để hai instance
+// không chạy migration cùng lúc
+const lockName = "vadilatorgolang.schema_migrations"
+
+// errTableNotFound là mã lỗi MySQL ER_NO_SUCH_TABLE
+const errTableNotFound = 1146
+
+var (
+	ErrLocked           = errors.New("migrate: đang có tiến trình khác chạy migration")
+	ErrChecksumMismatch = errors.New("migrate: checksum của migration đã chạy không khớp với file")
+	ErrNoDownScript     = errors.New("migrate: migration không có file .down.sql")
+	ErrPending          = errors.New("migrate: còn migration chưa chạy")
+)
+
+// Migrator áp dụng các Migration lên database và ghi lại vào bảng schema_migrations
+type Migrator struct {
+	DB          *sql.DB
+	Migrations  []Migration
+	LockTimeout time.Duration
+}
+
+// Status là trạng thái của một migration so với database
+type Status struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+	// Modified = true khi file đã bị sửa sau khi migration được áp dụng
+	Modified bool
+}
+
+// New tạo Migrator từ các file migration trong fsys
+func New(db *sql.DB, fsys fs.FS) (*Migrator, error) {
+	migrations, err := Load(fsys)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{DB: db, Migrations: migrations, LockTimeout: 10 * time.Second}, nil
+}
+
+// Up áp dụng tối đa n migration đang chờ (n <= 0 là tất cả), trả về các migration đã chạy
+func (m *Migrator) Up(ctx context.Context, n int) ([]Migration, error) {
+	var done []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		statuses, err := m.status(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if err := checkModified(statuses); err != nil {
+			return err
+		}
+		for _, s := range statuses {
+			if s.Applied {
+				continue
+			}
+			if n > 0 && len(done) >= n {
+				break
+			}
+			if err := m.apply(ctx, conn, s.Migration, true); err != nil {
+				return err
+			}
+			done = append(done, s.Migration)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Down hoàn tác n migration gần nhất (n <= 0 được hiểu là 1)
+func (m *Migrator) Down(ctx context.Context, n int) ([]Migration, error) {
+	if n <= 0 {
+		n = 1
+	}
+	var done []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		statuses, err := m.status(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for i := len(statuses) - 1; i >= 0 && len(done) < n; i-- {
+			if !statuses[i].Applied {
+				continue
+			}
+			if err := m.apply(ctx, conn, statuses[i].Migration, false); err != nil {
+				return err
+			}
+			done = append(done, statuses[i].Migration)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Redo hoàn tác rồi áp dụng lại migration gần nhất
+func (m *Migrator) Redo(ctx context.Context) (*Migration, error) {
+	var redone *Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		statuses, err := m.status(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for i := len(statuses) - 1; i >= 0; i-- {
+			if !statuses[i].Applied {
+				continue
+			}
+			mig := statuses[i].Migration
+			if err := m.apply(ctx, conn, mig, false); err != nil {
+				return err
+			}
+			if err := m.apply(ctx, conn, mig, true); err != nil {
+				return err
+			}
+			redone = &mig
+			return nil
+		}
+		return nil
+	})
+	return redone, err
+}
+
+// Status trả về trạng thái của mọi migration đã biết
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	conn, err := m.DB.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	return m.status(ctx, conn)
+}
+
+// Pending trả về các migration chưa được áp dụng
+func (m *Migrator) Pending(ctx context.Context) ([]Migration, error) {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var pending []Migration
+	for _, s := range statuses {
+		if !s.Applied {
+			pending = append(pending, s.Migration)
+		}
+	}
+	return pending, nil
+}
+
+// Check trả về ErrPending kèm migration chờ đầu tiên nếu schema chưa cập
+// nhật, dùng làm health check
+func (m *Migrator) Check(ctx context.Context) error {
+	pending, err := m.Pending(ctx)
+	if err != nil {
+		return err
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("%w: %d, bắt đầu từ %04d_%s", ErrPending, len(pending), pending[0].Version, pending[0].Name)
+	}
+	return nil
+}
+
+func ensureTable(ctx context.Context, conn *sql.Conn) error {
+	_, err := conn.ExecContext(ctx, `create table if not exists schema_migrations (
+		version    bigint       not null primary key,
+		name       varchar(255) not null,
+		checksum   char(64)     not null,
+		applied_at datetime     not null
+	)`)
+	return err
+}
+
+type appliedRow struct {
+	checksum string
+	at       time.Time
+}
+
+// applied đọc bảng schema_migrations. Bảng chưa tồn tại nghĩa là chưa chạy
+// migration nào; không tạo bảng ở đây vì đây là thao tác chỉ đọc.
+func applied(ctx context.Context, conn *sql.Conn) (map[int64]appliedRow, error) {
+	done := make(map[int64]appliedRow)
+	rows, err := conn.QueryContext(ctx, "select version,checksum,applied_at from schema_migrations")
+	var myErr *mysql.MySQLError
+	if errors.As(err, &myErr) && myErr.Number == errTableNotFound {
+		return done, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var v int64
+		var a appliedRow
+		if err := rows.Scan(&v, &a.checksum, &a.at); err != nil {
+			return nil, err
+		}
+		done[v] = a
+	}
+	return done, rows.Err()
+}
+
+func (m *Migrator) status(ctx context.Context, conn *sql.Conn) ([]Status, error) {
+	done, err := applied(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+	return statusOf(m.Migrations, done), nil
+}
+
+// statusOf so các migration với các dòng đã có trong schema_migrations
+func statusOf(migrations []Migration, done map[int64]appliedRow) []Status {
+	statuses := make([]Status, len(migrations))
+	for i, mig := range migrations {
+		statuses[i].Migration = mig
+		if a, ok := done[mig.Version]; ok {
+			statuses[i].Applied = true
+			statuses[i].AppliedAt = a.at
+			statuses[i].Modified = a.checksum != mig.Checksum
+		}
+	}
+	return statuses
+}
+
+// checkModified trả về ErrChecksumMismatch cho migration đầu tiên có file bị
+// sửa sau khi đã áp dụng
+func checkModified(statuses []Status) error {
+	for _, s := range statuses {
+		if s.Modified {
+			return fmt.Errorf("%w: %d_%s", ErrChecksumMismatch, s.Version, s.Name)
+		}
+	}
+	return nil
+}
+
+// apply chạy script up/down và cập nhật bảng schema_migrations.
+// DDL của MySQL tự commit nên không bọc trong transaction.
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, mig Migration, up bool) error {
+	script := mig.Up
+	if !up {
+		if mig.Down == "" {
+			return fmt.Errorf("%w: %d_%s", ErrNoDownScript, mig.Version, mig.Name)
+		}
+		script = mig.Down
+	}
+
+	for _, stmt := range splitStatements(script) {
+		if _, err := conn.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("migration %d_%s thất bại: %w", mig.Version, mig.Name, err)
+		}
+	}
+
+	var err error
+	if up {
+		_, err = conn.ExecContext(ctx, "insert into schema_migrations(version,name,checksum,applied_at) values(?,?,?,?)",
+			mig.Version, mig.Name, mig.Checksum, time.Now().UTC())
+	} else {
+		_, err = conn.ExecContext(ctx, "delete from schema_migrations where version=?", mig.Version)
+	}
+	return err
+}
+
+// withLock giữ advisory lock trên một kết nối riêng trong suốt thời gian chạy fn
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.DB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	var got sql.NullInt64
+	timeout := int(m.LockTimeout / time.Second)
+	if err := conn.QueryRowContext(ctx, "select get_lock(?, ?)", lockName, timeout).Scan(&got); err != nil {
+		return err
+	}
+	if !got.Valid || got.Int64 != 1 {
+		return ErrLocked
+	}
+	defer conn.ExecContext(context.Background(), "select release_lock(?)", lockName)
+
+	if err := ensureTable(ctx, conn); err != nil {
+		return err
+	}
+	return fn(conn)
+}
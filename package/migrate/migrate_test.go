@@ -0,0 +1,40 @@
+package migrate
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStatusChecksum(t *testing.T) {
+	migs := []Migration{
+		{Version: 1, Name: "tao_bang", Checksum: "aaa"},
+		{Version: 2, Name: "them_cot", Checksum: "bbb"},
+		{Version: 3, Name: "tao_index", Checksum: "ccc"},
+	}
+	at := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	statuses := statusOf(migs, map[int64]appliedRow{1: {checksum: "aaa", at: at}, 2: {checksum: "bbb", at: at}})
+	for i, s := range statuses {
+		if s.Applied != (i < 2) || s.Modified {
+			t.Errorf("%d: Applied=%v Modified=%v", s.Version, s.Applied, s.Modified)
+		}
+	}
+	if !statuses[0].AppliedAt.Equal(at) {
+		t.Errorf("AppliedAt = %v", statuses[0].AppliedAt)
+	}
+	if err := checkModified(statuses); err != nil {
+		t.Errorf("checkModified = %v, muốn nil", err)
+	}
+
+	// File của version 2 bị sửa sau khi đã chạy
+	statuses = statusOf(migs, map[int64]appliedRow{1: {checksum: "aaa"}, 2: {checksum: "cu"}})
+	if !statuses[1].Modified || statuses[0].Modified || statuses[2].Modified {
+		t.Errorf("Modified = %v %v %v, muốn chỉ version 2", statuses[0].Modified, statuses[1].Modified, statuses[2].Modified)
+	}
+	err := checkModified(statuses)
+	if !errors.Is(err, ErrChecksumMismatch) || !strings.Contains(err.Error(), "2_them_cot") {
+		t.Errorf("checkModified = %v, muốn ErrChecksumMismatch cho 2_them_cot", err)
+	}
+}
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS nguoi_dung;
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS nguoi_dung (
+    id         INT          NOT NULL AUTO_INCREMENT,
+    username   VARCHAR(50)  NOT NULL,
+    email      VARCHAR(255) NOT NULL,
+    age        INT          NOT NULL DEFAULT 0,
+    created_at DATETIME     NOT NULL,
+    PRIMARY KEY (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
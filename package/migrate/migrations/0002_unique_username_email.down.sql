@@ -0,0 +1,3 @@
+ALTER TABLE nguoi_dung
+    DROP INDEX uq_nguoi_dung_username,
+    DROP INDEX uq_nguoi_dung_email;
@@ -0,0 +1,5 @@
+-- Index unique là nguồn sự thật cho việc trùng username/email,
+-- controller không còn tự kiểm tra trước khi insert.
+ALTER TABLE nguoi_dung
+    ADD CONSTRAINT uq_nguoi_dung_username UNIQUE (username),
+    ADD CONSTRAINT uq_nguoi_dung_email UNIQUE (email);
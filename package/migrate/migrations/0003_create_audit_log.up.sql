@@ -0,0 +1,16 @@
+-- Nhật ký thay đổi dữ liệu (ai, làm gì, lúc nào, thay đổi những trường nào).
+-- changes là JSON dạng {"field": {"old": ..., "new": ...}}.
+CREATE TABLE IF NOT EXISTS audit_log (
+    id          BIGINT       NOT NULL AUTO_INCREMENT,
+    actor       VARCHAR(255) NOT NULL,
+    action      VARCHAR(16)  NOT NULL,
+    target_type VARCHAR(32)  NOT NULL,
+    target_id   INT          NOT NULL,
+    request_id  VARCHAR(64)  NOT NULL DEFAULT '',
+    changes     JSON         NOT NULL,
+    created_at  DATETIME(6)  NOT NULL,
+    PRIMARY KEY (id),
+    KEY idx_audit_log_target (target_type, target_id, created_at),
+    KEY idx_audit_log_actor (actor, created_at),
+    KEY idx_audit_log_created_at (created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
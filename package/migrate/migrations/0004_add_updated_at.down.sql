@@ -0,0 +1 @@
+ALTER TABLE nguoi_dung DROP COLUMN updated_at;
@@ -0,0 +1,5 @@
+-- updated_at cho response v2 (docs.yaml). Bản ghi cũ lấy theo created_at
+-- rồi mới đặt NOT NULL.
+ALTER TABLE nguoi_dung ADD COLUMN updated_at DATETIME NULL AFTER created_at;
+UPDATE nguoi_dung SET updated_at = created_at WHERE updated_at IS NULL;
+ALTER TABLE nguoi_dung MODIFY updated_at DATETIME NOT NULL;
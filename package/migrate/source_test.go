@@ -0,0 +1,120 @@
+package migrate
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   []string
+	}{
+		{"hai câu lệnh", "create table a (x int);\ninsert into a values (1);\n",
+			[]string{"create table a (x int)", "insert into a values (1)"}},
+		{"câu cuối không có ;", "select 1;\nselect 2", []string{"select 1", "select 2"}},
+		{"; trong nháy đơn", "insert into a values ('x;y'); select 1",
+			[]string{"insert into a values ('x;y')", "select 1"}},
+		{"; trong nháy kép và backtick", "select \"a;b\", `c;d` from t;",
+			[]string{"select \"a;b\", `c;d` from t"}},
+		{"nháy thoát bằng \\", `insert into a values ('it\'s; ok');`,
+			[]string{`insert into a values ('it\'s; ok')`}},
+		{"nháy kép trong nháy đơn", `select 'say "hi;"';`, []string{`select 'say "hi;"'`}},
+		{"comment --", "-- tạo bảng; có dấu chấm phẩy\ncreate table a (x int);",
+			[]string{"create table a (x int)"}},
+		{"comment #", "# ghi chú; ở đây\nselect 1;", []string{"select 1"}},
+		{"comment cuối dòng", "select 1; -- xong;\nselect 2; # hết;\n", []string{"select 1", "select 2"}},
+		{"comment giữa câu lệnh", "select 1 -- giữa;\n, 2;", []string{"select 1 \n, 2"}},
+		{"-- trong chuỗi không phải comment", "select '--;', '#;';", []string{"select '--;', '#;'"}},
+		{"câu lệnh rỗng", " ;;\n ; ", nil},
+		{"chỉ có comment", "-- không có gì\n# cả\n", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := splitStatements(tt.script); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitStatements(%q) = %q, muốn %q", tt.script, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadOrderAndChecksum(t *testing.T) {
+	fsys := fstest.MapFS{
+		"10_them_cot.up.sql":     {Data: []byte("alter table a add y int;")},
+		"9_tao_index.up.sql":     {Data: []byte("create index i on a (x);")},
+		"9_tao_index.down.sql":   {Data: []byte("drop index i on a;")},
+		"0001_tao_bang.up.sql":   {Data: []byte("create table a (x int);")},
+		"0001_tao_bang.down.sql": {Data: []byte("drop table a;")},
+	}
+	migs, err := Load(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var versions []int64
+	for _, m := range migs {
+		versions = append(versions, m.Version)
+	}
+	// Sắp theo số, không theo thứ tự chữ của tên file ("10" < "9")
+	if want := []int64{1, 9, 10}; !reflect.DeepEqual(versions, want) {
+		t.Fatalf("versions = %v, muốn %v", versions, want)
+	}
+	if migs[1].Name != "tao_index" || migs[1].Down != "drop index i on a;" || migs[2].Down != "" {
+		t.Errorf("migration = %+v", migs)
+	}
+
+	// Checksum chỉ phụ thuộc file up
+	sum := migs[0].Checksum
+	fsys["0001_tao_bang.down.sql"] = &fstest.MapFile{Data: []byte("drop table if exists a;")}
+	migs, _ = Load(fsys)
+	if migs[0].Checksum != sum {
+		t.Error("sửa file down làm đổi checksum")
+	}
+	fsys["0001_tao_bang.up.sql"] = &fstest.MapFile{Data: []byte("create table a (x bigint);")}
+	migs, _ = Load(fsys)
+	if migs[0].Checksum == sum {
+		t.Error("sửa file up không làm đổi checksum")
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		fsys fstest.MapFS
+		want string
+	}{
+		{"tên file sai", fstest.MapFS{"tao_bang.sql": {}}, "tên file"},
+		{"trùng version", fstest.MapFS{
+			"1_a.up.sql": {Data: []byte("select 1")},
+			"1_b.up.sql": {Data: []byte("select 2")},
+		}, "bị trùng"},
+		{"thiếu file up", fstest.MapFS{"1_a.down.sql": {Data: []byte("select 1")}}, "thiếu file .up.sql"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Load(tt.fsys); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, muốn lỗi chứa %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestEmbeddedMigrations(t *testing.T) {
+	migs, err := Load(Embedded())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, m := range migs {
+		if i > 0 && m.Version <= migs[i-1].Version {
+			t.Errorf("version %d đứng sau %d", m.Version, migs[i-1].Version)
+		}
+		if len(splitStatements(m.Up)) == 0 {
+			t.Errorf("%d_%s: file up không có câu lệnh nào", m.Version, m.Name)
+		}
+		if m.Down == "" {
+			t.Errorf("%d_%s: thiếu file down", m.Version, m.Name)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// fakeClock cho phép test điều khiển thời gian của MemoryStore
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time          { return c.t }
+func (c *fakeClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newTestStore() (*MemoryStore, *fakeClock) {
+	clock := &fakeClock{t: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	s := NewMemoryStore()
+	s.now = clock.now
+	return s, clock
+}
+
+func TestMemoryStoreAllow(t *testing.T) {
+	s, clock := newTestStore()
+	ctx := context.Background()
+	l := Limit{Rate: 10, Period: 10 * time.Second, Burst: 3} // 1 token/giây, dồn tối đa 3
+
+	for i := 0; i < 3; i++ {
+		res, _ := s.Allow(ctx, "a", l)
+		if !res.Allowed || res.Remaining != 2-i || res.Limit != 3 {
+			t.Fatalf("lần %d: %+v", i, res)
+		}
+	}
+	res, _ := s.Allow(ctx, "a", l)
+	if res.Allowed || res.RetryAfter != time.Second || res.Reset != 3*time.Second {
+		t.Fatalf("hết token: %+v", res)
+	}
+
+	// Key khác có bucket riêng
+	if res, _ := s.Allow(ctx, "b", l); !res.Allowed {
+		t.Fatalf("key b: %+v", res)
+	}
+
+	// Nạp lại theo thời gian, không vượt quá Burst
+	clock.advance(1500 * time.Millisecond)
+	if res, _ := s.Allow(ctx, "a", l); !res.Allowed || res.Remaining != 0 {
+		t.Fatalf("sau 1.5s: %+v", res)
+	}
+	clock.advance(time.Hour)
+	if res, _ := s.Allow(ctx, "a", l); !res.Allowed || res.Remaining != 2 {
+		t.Fatalf("sau 1h: %+v", res)
+	}
+}
+
+func TestMemoryStoreUnlimited(t *testing.T) {
+	s, _ := newTestStore()
+	for _, l := range []Limit{{Rate: 0, Period: time.Second}, {Rate: 5}} {
+		for i := 0; i < 100; i++ {
+			if res, _ := s.Allow(context.Background(), "a", l); !res.Allowed {
+				t.Fatalf("%+v bị từ chối ở lần %d", l, i)
+			}
+		}
+	}
+	if s.Len() != 0 {
+		t.Errorf("không giới hạn mà vẫn tạo %d bucket", s.Len())
+	}
+}
+
+func TestMemoryStoreSweep(t *testing.T) {
+	s, clock := newTestStore()
+	l := Limit{Rate: 1, Period: time.Second}
+	s.Allow(context.Background(), "a", l)
+	s.Allow(context.Background(), "b", l)
+
+	// Bucket đã đầy lại thì bị dọn ở lần Allow sau sweepInterval
+	clock.advance(sweepInterval)
+	s.Allow(context.Background(), "c", l)
+	if s.Len() != 1 {
+		t.Errorf("Len = %d, muốn 1 sau khi dọn", s.Len())
+	}
+}
+
+func TestLimitString(t *testing.T) {
+	tests := []struct {
+		l    Limit
+		want string
+	}{
+		{Limit{Rate: 10, Period: time.Minute}, "10;w=60"},
+		{Limit{Rate: 10, Period: time.Minute, Burst: 10}, "10;w=60"},
+		{Limit{Rate: 10, Period: time.Minute, Burst: 20}, "10;w=60;burst=20"},
+	}
+	for _, tt := range tests {
+		if got := tt.l.String(); got != tt.want {
+			t.Errorf("%+v.String() = %q, muốn %q", tt.l, got, tt.want)
+		}
+	}
+}
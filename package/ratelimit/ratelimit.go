@@ -0,0 +1,59 @@
+// Package ratelimit giới hạn tần suất theo thuật toán token bucket. Việc
+// tính toán nằm trong Store để một backend dùng chung (Redis...) có thể làm
+// nguyên tử ở phía nó; MemoryStore dùng cho server chạy một instance.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Limit cho phép Rate request mỗi Period, dồn tối đa Burst request một lúc.
+// Burst = 0 nghĩa là bằng Rate. Rate = 0 nghĩa là không giới hạn.
+type Limit struct {
+	Rate   int
+	Period time.Duration
+	Burst  int
+}
+
+// Capacity là số token tối đa của bucket
+func (l Limit) Capacity() int {
+	if l.Burst > 0 {
+		return l.Burst
+	}
+	return l.Rate
+}
+
+// Unlimited báo l không giới hạn gì
+func (l Limit) Unlimited() bool {
+	return l.Rate <= 0 || l.Period <= 0
+}
+
+// perSecond là tốc độ nạp lại token
+func (l Limit) perSecond() float64 {
+	return float64(l.Rate) / l.Period.Seconds()
+}
+
+// String dùng cho header RateLimit-Policy: "10;w=60;burst=20"
+func (l Limit) String() string {
+	s := fmt.Sprintf("%d;w=%d", l.Rate, int(l.Period.Seconds()))
+	if l.Burst > 0 && l.Burst != l.Rate {
+		s += fmt.Sprintf(";burst=%d", l.Burst)
+	}
+	return s
+}
+
+// Result là kết quả của một lần lấy token
+type Result struct {
+	Allowed    bool
+	Limit      int           // dung lượng bucket
+	Remaining  int           // số request còn được phép ngay lúc này
+	Reset      time.Duration // thời gian tới khi bucket đầy lại
+	RetryAfter time.Duration // khi bị từ chối: thời gian tới khi có lại một token
+}
+
+// Store lấy một token của key theo l. Cài đặt phải an toàn khi gọi đồng thời.
+type Store interface {
+	Allow(ctx context.Context, key string, l Limit) (Result, error)
+}
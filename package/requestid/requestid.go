@@ -0,0 +1,39 @@
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+// Header là header HTTP mang request ID giữa client, proxy và server
+const Header = "X-Request-ID"
+
+type ctxKey struct{}
+
+// New sinh một request ID ngẫu nhiên 16 byte dạng hex
+func New() string {
+	var b [16]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// NewContext gắn request ID vào ctx
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, id)
+}
+
+// FromContext lấy request ID trong ctx, rỗng nếu không có
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(ctxKey{}).(string)
+	return id
+}
+
+// FromRequest ưu tiên ID trong context, sau đó tới header của request
+func FromRequest(r *http.Request) string {
+	if id := FromContext(r.Context()); id != "" {
+		return id
+	}
+	return r.Header.Get(Header)
+}
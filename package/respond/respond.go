@@ -0,0 +1,29 @@
+// Package respond ghi response của handler: body JSON khi thành công, và
+// problem+json (qua apperr.WriteProblem) kèm một dòng log khi lỗi.
+package respond
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"vadilatorgolang/package/apperr"
+	"vadilatorgolang/package/logger"
+)
+
+// JSON ghi data dạng JSON với status
+func JSON(w http.ResponseWriter, status int, data any) {
+	w.Header().Set("content-type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+// Error dịch err qua apperr rồi ghi response. Lỗi 5xx ghi vào ERROR kèm
+// nguyên nhân gốc, lỗi 4xx ghi vào WARN; client chỉ nhận message an toàn.
+func Error(w http.ResponseWriter, r *http.Request, err error) {
+	e := apperr.WriteProblem(w, r, err)
+	level := logger.LevelWarn
+	if e.Kind.HTTPStatus() >= http.StatusInternalServerError {
+		level = logger.LevelError
+	}
+	logger.FromRequest(r).Log(r.Context(), level, "Lỗi xử lý request", "code", e.Code, "error", err)
+}
@@ -0,0 +1,158 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"vadilatorgolang/package/requestid"
+	"vadilatorgolang/package/tracing"
+)
+
+// Định dạng access log
+const (
+	AccessLogCommon   = "common"
+	AccessLogCombined = "combined"
+	AccessLogJSON     = "json"
+)
+
+// clfTimeFormat là định dạng thời gian của Common Log Format
+const clfTimeFormat = "02/Jan/2006:15:04:05 -0700"
+
+// responseRecorder ghi nhận status và số byte đã ghi của response
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (w *responseRecorder) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseRecorder) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(p)
+	w.bytes += int64(n)
+	return n, err
+}
+
+// Unwrap cho http.ResponseController truy cập Flush/Hijack... của writer gốc
+func (w *responseRecorder) Unwrap() http.ResponseWriter { return w.ResponseWriter }
+
+// Flush chuyển tiếp nếu writer gốc hỗ trợ
+func (w *responseRecorder) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		if w.status == 0 {
+			w.status = http.StatusOK
+		}
+		f.Flush()
+	}
+}
+
+// accessEntry là một dòng access log dạng JSON
+type accessEntry struct {
+	Time      time.Time `json:"time"`
+	RemoteIP  string    `json:"remote_ip"`
+	Method    string    `json:"method"`
+	Path      string    `json:"path"`
+	Route     string    `json:"route,omitempty"`
+	Proto     string    `json:"proto"`
+	Status    int       `json:"status"`
+	Bytes     int64     `json:"bytes"`
+	Duration  float64   `json:"duration_ms"`
+	RequestID string    `json:"request_id,omitempty"`
+	TraceID   string    `json:"trace_id,omitempty"`
+	Referer   string    `json:"referer,omitempty"`
+	UserAgent string    `json:"user_agent,omitempty"`
+}
+
+// AccessLog ghi một dòng cho mỗi request vào out theo format
+// (common, combined hoặc json). Với common/combined, sau các trường chuẩn
+// được thêm thời gian xử lý (ms), request ID và route pattern.
+func AccessLog(out io.Writer, format string, trusted TrustedProxies) func(http.Handler) http.Handler {
+	var mu sync.Mutex
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rec := &responseRecorder{ResponseWriter: w}
+			r = WithRouteSlot(r)
+			next.ServeHTTP(rec, r)
+
+			if rec.status == 0 {
+				rec.status = http.StatusOK
+			}
+			e := accessEntry{
+				Time:      start,
+				RemoteIP:  clientIP(r, trusted),
+				Method:    r.Method,
+				Path:      r.URL.RequestURI(),
+				Route:     RoutePattern(r),
+				Proto:     r.Proto,
+				Status:    rec.status,
+				Bytes:     rec.bytes,
+				Duration:  float64(time.Since(start).Microseconds()) / 1000,
+				RequestID: requestid.FromRequest(r),
+				Referer:   r.Referer(),
+				UserAgent: r.UserAgent(),
+			}
+
+			if sc := tracing.SpanContextFromContext(r.Context()); sc.IsValid() {
+				e.TraceID = sc.TraceID.String()
+			}
+
+			var line []byte
+			switch format {
+			case AccessLogJSON:
+				line, _ = json.Marshal(e)
+				line = append(line, '\n')
+			case AccessLogCombined:
+				line = fmt.Appendf(nil, "%s %q %q %s\n", clfLine(e), dash(e.Referer), dash(e.UserAgent), clfExtra(e))
+			default:
+				line = fmt.Appendf(nil, "%s %s\n", clfLine(e), clfExtra(e))
+			}
+
+			mu.Lock()
+			out.Write(line)
+			mu.Unlock()
+		})
+	}
+}
+
+// clientIP ưu tiên IP do middleware RealIP đã xác định
+func clientIP(r *http.Request, trusted TrustedProxies) string {
+	if ip, ok := r.Context().Value(clientIPKey{}).(string); ok {
+		return ip
+	}
+	return trusted.ClientIP(r)
+}
+
+// clfLine: host ident authuser [date] "request" status bytes
+func clfLine(e accessEntry) string {
+	size := "-"
+	if e.Bytes > 0 {
+		size = strconv.FormatInt(e.Bytes, 10)
+	}
+	return fmt.Sprintf(`%s - - [%s] "%s %s %s" %d %s`,
+		e.RemoteIP, e.Time.Format(clfTimeFormat), e.Method, e.Path, e.Proto, e.Status, size)
+}
+
+func clfExtra(e accessEntry) string {
+	return fmt.Sprintf("%.3fms %s %q", e.Duration, dash(e.RequestID), dash(e.Route))
+}
+
+func dash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,89 @@
+package server
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"vadilatorgolang/internal/audit"
+	"vadilatorgolang/package/apperr"
+	"vadilatorgolang/package/logger"
+)
+
+var (
+	// ErrUnauthorized trả về khi thiếu hoặc sai token quản trị
+	ErrUnauthorized = apperr.Unauthorized("unauthorized", "Thiếu hoặc sai token quản trị")
+	// ErrInvalidAPIKey trả về khi API key sai, hoặc thiếu trong khi bắt buộc
+	ErrInvalidAPIKey = apperr.Unauthorized("invalid_api_key", "Thiếu hoặc sai API key")
+)
+
+// AdminAuth chỉ cho request có header "Authorization: Bearer <token>" đúng
+// token đi tiếp. token rỗng thì từ chối mọi request.
+func AdminAuth(token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || token == "" || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="admin"`)
+			apperr.WriteProblem(w, r, ErrUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// APIKeys ánh xạ API key sang actor
+type APIKeys map[string]string
+
+// ParseAPIKeys đọc danh sách "actor:key" trong cấu hình
+func ParseAPIKeys(entries []string) (APIKeys, error) {
+	keys := APIKeys{}
+	for _, e := range entries {
+		actor, key, ok := strings.Cut(e, ":")
+		actor, key = strings.TrimSpace(actor), strings.TrimSpace(key)
+		if !ok || actor == "" || key == "" {
+			return nil, fmt.Errorf("API key phải có dạng actor:key (actor %q)", actor)
+		}
+		if _, dup := keys[key]; dup {
+			return nil, fmt.Errorf("API key của actor %q bị trùng", actor)
+		}
+		keys[key] = actor
+	}
+	return keys, nil
+}
+
+// Lookup trả về actor của key. So sánh hằng thời gian với mọi key để không
+// lộ key qua thời gian phản hồi.
+func (k APIKeys) Lookup(key string) (actor string, ok bool) {
+	for candidate, a := range k {
+		if subtle.ConstantTimeCompare([]byte(candidate), []byte(key)) == 1 {
+			actor, ok = a, true
+		}
+	}
+	return actor, ok
+}
+
+// APIKeyAuth xác thực API key trong header rồi gắn actor vào context (audit
+// log ghi lại người thực hiện) và logger của request. Key sai luôn bị từ
+// chối; thiếu key thì từ chối khi required, còn không thì đi tiếp với actor
+// anonymous.
+func APIKeyAuth(header string, keys APIKeys, required bool) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(header)
+			if key == "" && !required {
+				next.ServeHTTP(w, r)
+				return
+			}
+			actor, ok := keys.Lookup(key)
+			if !ok {
+				logger.FromRequest(r).Warn("API key không hợp lệ", "present", key != "")
+				apperr.WriteProblem(w, r, ErrInvalidAPIKey)
+				return
+			}
+			ctx := audit.WithActor(r.Context(), actor)
+			ctx = logger.NewContext(ctx, logger.FromRequest(r).With("actor", actor))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
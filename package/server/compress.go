@@ -0,0 +1,268 @@
+package server
+
+import (
+	"bufio"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"vadilatorgolang/package/config"
+)
+
+// skipCompressTypes là các Content-Type đã nén sẵn, nén lại chỉ tốn CPU
+var skipCompressTypes = []string{
+	"image/", "video/", "audio/", "font/woff",
+	"application/zip", "application/gzip", "application/x-gzip",
+	"application/zstd", "application/x-7z-compressed", "application/x-rar-compressed",
+	"application/pdf", "application/octet-stream",
+}
+
+// Compress nén response bằng gzip hoặc deflate theo Accept-Encoding.
+// Phần đầu của body được giữ lại tới MinSize byte để quyết định: body nhỏ
+// hơn thì gửi nguyên. Gọi Flush trước khi đủ MinSize (streaming) thì nén luôn.
+// Không nén response đã có Content-Encoding, kiểu đã nén sẵn, 204/304/206.
+// Writer được lấy từ sync.Pool để tránh cấp phát mỗi request.
+func Compress(cfg config.CompressionConfig) Middleware {
+	level := cfg.Level
+	gzipPool := sync.Pool{New: func() any {
+		w, _ := gzip.NewWriterLevel(io.Discard, level)
+		return w
+	}}
+	deflatePool := sync.Pool{New: func() any {
+		w, _ := zlib.NewWriterLevel(io.Discard, level)
+		return w
+	}}
+
+	return func(next http.Handler) http.Handler {
+		if !cfg.Enabled {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Encoding")
+			enc := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+			if enc == "" || r.Method == http.MethodHead || r.Header.Get("Range") != "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			cw := &compressWriter{ResponseWriter: w, encoding: enc, minSize: cfg.MinSize}
+			switch enc {
+			case "gzip":
+				cw.pool = &gzipPool
+			case "deflate":
+				cw.pool = &deflatePool
+			}
+			defer func() {
+				// Khi panic không được gửi gì (Close sẽ gửi 200), để Recover còn
+				// trả 500 thay vì ngắt kết nối giữa luồng nén
+				if v := recover(); v != nil {
+					cw.abort()
+					panic(v)
+				}
+				cw.Close()
+			}()
+			next.ServeHTTP(cw, r)
+		})
+	}
+}
+
+// negotiateEncoding chọn gzip hoặc deflate theo q-value (ưu tiên gzip khi
+// bằng nhau), "" nếu client không nhận cả hai
+func negotiateEncoding(header string) string {
+	best, bestQ := "", 0.0
+	wildcard := -1.0
+	seen := map[string]bool{}
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				q = f
+			}
+		}
+		switch name {
+		case "*":
+			wildcard = q
+			continue
+		case "x-gzip":
+			name = "gzip"
+		case "gzip", "deflate":
+		default:
+			continue
+		}
+		seen[name] = true
+		if q > bestQ || (q == bestQ && name == "gzip") {
+			best, bestQ = name, q
+		}
+	}
+	// "*" áp cho các encoding chưa được nêu tên
+	if wildcard > bestQ {
+		for _, name := range []string{"gzip", "deflate"} {
+			if !seen[name] {
+				return name
+			}
+		}
+	}
+	if bestQ <= 0 {
+		return ""
+	}
+	return best
+}
+
+// encoder là phần chung của *gzip.Writer và *zlib.Writer
+type encoder interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// compressWriter giữ phần đầu của body cho tới khi quyết định có nén hay không
+type compressWriter struct {
+	http.ResponseWriter
+	encoding string
+	minSize  int
+	pool     *sync.Pool
+
+	status  int
+	buf     []byte
+	decided bool
+	enc     encoder // khác nil khi đang nén
+}
+
+func (w *compressWriter) WriteHeader(status int) {
+	if w.decided {
+		// Để net/http cảnh báo "superfluous WriteHeader" như bình thường
+		w.ResponseWriter.WriteHeader(status)
+		return
+	}
+	if w.status != 0 {
+		return
+	}
+	// 1xx (ví dụ 103 Early Hints) đi thẳng, không phải status cuối
+	if status >= 100 && status < 200 {
+		w.ResponseWriter.WriteHeader(status)
+		return
+	}
+	w.status = status
+	if status == http.StatusNoContent || status == http.StatusNotModified || status == http.StatusPartialContent {
+		w.decide(false)
+	}
+}
+
+func (w *compressWriter) Write(p []byte) (int, error) {
+	if !w.decided {
+		w.buf = append(w.buf, p...)
+		if len(w.buf) < w.minSize {
+			return len(p), nil
+		}
+		if err := w.decide(true); err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	}
+	if w.enc != nil {
+		return w.enc.Write(p)
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+// decide gửi header và phần body đang giữ. big = false nghĩa là body nhỏ
+// hơn MinSize nên không nén.
+func (w *compressWriter) decide(big bool) error {
+	w.decided = true
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	h := w.Header()
+	if h.Get("Content-Type") == "" && len(w.buf) > 0 {
+		// Phải đoán kiểu trên dữ liệu gốc, net/http chỉ thấy dữ liệu đã nén
+		h.Set("Content-Type", http.DetectContentType(w.buf))
+	}
+
+	if big && w.status != http.StatusNoContent && w.status != http.StatusNotModified &&
+		w.status != http.StatusPartialContent && h.Get("Content-Encoding") == "" &&
+		compressible(h.Get("Content-Type")) {
+		h.Set("Content-Encoding", w.encoding)
+		h.Del("Content-Length")
+		h.Del("Accept-Ranges")
+		w.enc = w.pool.Get().(encoder)
+		w.enc.Reset(w.ResponseWriter)
+	}
+
+	w.ResponseWriter.WriteHeader(w.status)
+	buf := w.buf
+	w.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	var err error
+	if w.enc != nil {
+		_, err = w.enc.Write(buf)
+	} else {
+		_, err = w.ResponseWriter.Write(buf)
+	}
+	return err
+}
+
+func compressible(contentType string) bool {
+	ct := strings.ToLower(contentType)
+	for _, prefix := range skipCompressTypes {
+		if strings.HasPrefix(ct, prefix) {
+			return strings.HasPrefix(ct, "image/svg")
+		}
+	}
+	return true
+}
+
+// Flush gửi ngay phần đã có; với streaming thì bắt đầu nén luôn dù chưa đủ MinSize
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		w.decide(true)
+	}
+	if w.enc != nil {
+		w.enc.Flush()
+	}
+	http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Close kết thúc luồng nén và trả writer về pool
+func (w *compressWriter) Close() error {
+	if !w.decided {
+		if err := w.decide(false); err != nil {
+			return err
+		}
+	}
+	if w.enc == nil {
+		return nil
+	}
+	err := w.enc.Close()
+	w.enc.Reset(io.Discard)
+	w.pool.Put(w.enc)
+	w.enc = nil
+	return err
+}
+
+// abort trả writer về pool mà không ghi thêm gì; phần body đang giữ bị bỏ
+func (w *compressWriter) abort() {
+	w.buf = nil
+	if w.enc == nil {
+		return
+	}
+	w.enc.Reset(io.Discard)
+	w.pool.Put(w.enc)
+	w.enc = nil
+}
+
+// Hijack cho WebSocket; dữ liệu sau hijack không đi qua bộ nén
+func (w *compressWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return http.NewResponseController(w.ResponseWriter).Hijack()
+}
+
+// Unwrap cho http.ResponseController truy cập writer gốc
+func (w *compressWriter) Unwrap() http.ResponseWriter { return w.ResponseWriter }
@@ -0,0 +1,197 @@
+package server
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"vadilatorgolang/package/config"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", ""},
+		{"identity", ""},
+		{"gzip", "gzip"},
+		{"x-gzip", "gzip"},
+		{"deflate", "deflate"},
+		{"deflate, gzip", "gzip"},
+		{"GZIP;q=0.5, deflate;q=0.8", "deflate"},
+		{"gzip;q=0, deflate", "deflate"},
+		{"gzip;q=0, deflate;q=0", ""},
+		{"*", "gzip"},
+		{"gzip;q=0, *", "deflate"},
+		{"gzip;q=0.2, *;q=0.5", "deflate"},
+		{"br, zstd", ""},
+		{"*;q=0", ""},
+	}
+	for _, tt := range tests {
+		if got := negotiateEncoding(tt.header); got != tt.want {
+			t.Errorf("negotiateEncoding(%q) = %q, muốn %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+// serveCompressed chạy h qua Compress với Accept-Encoding = enc
+func serveCompressed(h http.HandlerFunc, enc string) *httptest.ResponseRecorder {
+	cfg := config.CompressionConfig{Enabled: true, Level: -1, MinSize: 64}
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	if enc != "" {
+		r.Header.Set("Accept-Encoding", enc)
+	}
+	w := httptest.NewRecorder()
+	Compress(cfg)(h).ServeHTTP(w, r)
+	return w
+}
+
+func decodeBody(t *testing.T, w *httptest.ResponseRecorder) string {
+	t.Helper()
+	var rd io.Reader = w.Body
+	var err error
+	switch w.Header().Get("Content-Encoding") {
+	case "gzip":
+		rd, err = gzip.NewReader(w.Body)
+	case "deflate":
+		rd, err = zlib.NewReader(w.Body)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := io.ReadAll(rd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestCompressWriter(t *testing.T) {
+	big := strings.Repeat(`{"name":"khanhchauu"},`, 20)
+	tests := []struct {
+		name        string
+		enc         string
+		contentType string
+		status      int
+		body        string
+		wantEnc     string
+	}{
+		{"json lớn gzip", "gzip", "application/json", 200, big, "gzip"},
+		{"json lớn deflate", "deflate", "application/json", 201, big, "deflate"},
+		{"client không nhận nén", "", "application/json", 200, big, ""},
+		{"body nhỏ", "gzip", "application/json", 200, `{"a":1}`, ""},
+		{"ảnh đã nén sẵn", "gzip", "image/png", 200, big, ""},
+		{"svg vẫn nén", "gzip", "image/svg+xml", 200, big, "gzip"},
+		{"đoán Content-Type", "gzip", "", 200, strings.Repeat("chữ ", 40), "gzip"},
+		{"204", "gzip", "", http.StatusNoContent, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serveCompressed(func(w http.ResponseWriter, r *http.Request) {
+				if tt.contentType != "" {
+					w.Header().Set("Content-Type", tt.contentType)
+				}
+				w.Header().Set("Content-Length", "999")
+				w.WriteHeader(tt.status)
+				// Ghi thành nhiều mảnh để qua được ngưỡng MinSize giữa chừng
+				for i := 0; i < len(tt.body); i += 10 {
+					io.WriteString(w, tt.body[i:min(i+10, len(tt.body))])
+				}
+			}, tt.enc)
+
+			if w.Code != tt.status {
+				t.Errorf("status = %d, muốn %d", w.Code, tt.status)
+			}
+			if got := w.Header().Get("Content-Encoding"); got != tt.wantEnc {
+				t.Fatalf("Content-Encoding = %q, muốn %q", got, tt.wantEnc)
+			}
+			if tt.wantEnc != "" && w.Header().Get("Content-Length") != "" {
+				t.Error("response nén vẫn giữ Content-Length cũ")
+			}
+			if got := decodeBody(t, w); got != tt.body {
+				t.Errorf("body = %q, muốn %q", got, tt.body)
+			}
+			if !strings.Contains(w.Header().Get("Vary"), "Accept-Encoding") {
+				t.Error("thiếu Vary: Accept-Encoding")
+			}
+			if tt.contentType == "" && tt.body != "" && !strings.HasPrefix(w.Header().Get("Content-Type"), "text/plain") {
+				t.Errorf("Content-Type = %q, muốn đoán từ dữ liệu gốc", w.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
+
+func TestCompressFlushStreams(t *testing.T) {
+	w := serveCompressed(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		io.WriteString(w, "data: 1\n\n")
+		http.NewResponseController(w).Flush()
+		if !w.(*compressWriter).decided {
+			t.Error("Flush chưa gửi header")
+		}
+		io.WriteString(w, "data: 2\n\n")
+	}, "gzip")
+
+	if w.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("Content-Encoding = %q, muốn gzip dù body nhỏ hơn MinSize", w.Header().Get("Content-Encoding"))
+	}
+	if !w.Flushed {
+		t.Error("Flush không tới writer gốc")
+	}
+	if got := decodeBody(t, w); got != "data: 1\n\ndata: 2\n\n" {
+		t.Errorf("body = %q", got)
+	}
+}
+
+func TestCompressSkipsRangeAndHead(t *testing.T) {
+	body := strings.Repeat("a", 200)
+	h := Compress(config.CompressionConfig{Enabled: true, Level: -1, MinSize: 64})(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) { io.WriteString(w, body) }))
+
+	for _, r := range []*http.Request{
+		httptest.NewRequest(http.MethodHead, "/", nil),
+		httptest.NewRequest(http.MethodGet, "/", nil),
+	} {
+		r.Header.Set("Accept-Encoding", "gzip")
+		if r.Method == http.MethodGet {
+			r.Header.Set("Range", "bytes=0-10")
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		if w.Header().Get("Content-Encoding") != "" || !bytes.Equal(w.Body.Bytes(), []byte(body)) {
+			t.Errorf("%s: response bị nén", r.Method)
+		}
+	}
+}
+
+func TestCompressPanicBeforeHeader(t *testing.T) {
+	cfg := config.CompressionConfig{Enabled: true, Level: -1, MinSize: 64}
+	h := Recover(false)(Compress(cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("phần đầu"))
+		panic("hỏng")
+	})))
+	r := httptest.NewRequest(http.MethodGet, "/users", nil)
+	r.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, muốn 500", w.Code)
+	}
+	if ce := w.Header().Get("Content-Encoding"); ce != "" {
+		t.Errorf("Content-Encoding = %q, muốn rỗng", ce)
+	}
+	var p struct {
+		IncidentID string `json:"incident_id"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &p); err != nil || p.IncidentID == "" {
+		t.Errorf("body = %q, muốn problem JSON có incident_id", w.Body.String())
+	}
+}
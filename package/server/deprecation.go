@@ -0,0 +1,34 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Deprecated đánh dấu route cũ: header Deprecation (RFC 9745) là thời điểm
+// route bị deprecate, Sunset (RFC 8594) là thời điểm dự kiến gỡ (bỏ qua nếu
+// zero), Link rel="successor-version" trỏ tới route thay thế do successor
+// tính từ path của request (bỏ qua nếu trả về "").
+func Deprecated(at, sunset time.Time, successor func(path string) string) Middleware {
+	deprecation := "@" + strconv.FormatInt(at.Unix(), 10)
+	var sunsetValue string
+	if !sunset.IsZero() {
+		sunsetValue = sunset.UTC().Format(http.TimeFormat)
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			h := w.Header()
+			h.Set("Deprecation", deprecation)
+			if sunsetValue != "" {
+				h.Set("Sunset", sunsetValue)
+			}
+			if successor != nil {
+				if path := successor(r.URL.Path); path != "" {
+					h.Add("Link", "<"+path+`>; rel="successor-version"`)
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+
+	"vadilatorgolang/package/config"
+	"vadilatorgolang/package/lifecycle"
+)
+
+// NewHTTPServer tạo http.Server với các giới hạn thời gian và kích thước header lấy từ cấu hình
+func NewHTTPServer(cfg config.ServerConfig, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              cfg.Addr(),
+		Handler:           handler,
+		ReadTimeout:       cfg.ReadTimeout,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+		MaxHeaderBytes:    cfg.MaxHeaderBytes,
+	}
+}
+
+// Hook gắn srv vào vòng đời của m. OnStart mở cổng ngay (lỗi bind trả về
+// đồng bộ) rồi phục vụ ở goroutine riêng; OnStop ngừng nhận kết nối mới
+// và chờ các request đang xử lý xong hoặc tới hạn của ctx.
+func Hook(name string, srv *http.Server, m *lifecycle.Manager) lifecycle.Hook {
+	return lifecycle.Hook{
+		Name: name,
+		OnStart: func(ctx context.Context) error {
+			ln, err := net.Listen("tcp", srv.Addr)
+			if err != nil {
+				return err
+			}
+			go func() {
+				if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+					m.Fail(err)
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			if err := srv.Shutdown(ctx); err != nil {
+				// Hết hạn mà vẫn còn request: cắt hẳn các kết nối còn lại
+				srv.Close()
+				return err
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,72 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"vadilatorgolang/package/metrics"
+)
+
+var (
+	httpRequestsTotal = metrics.NewCounterVec("http_requests_total",
+		"Số request HTTP đã xử lý theo method, route và status.", "method", "route", "status")
+	httpRequestDuration = metrics.NewHistogramVec("http_request_duration_seconds",
+		"Thời gian xử lý request HTTP theo method và route.", metrics.DefBuckets, "method", "route")
+	httpRequestsInFlight = metrics.NewGaugeVec("http_requests_in_flight",
+		"Số request HTTP đang được xử lý.")
+)
+
+func init() {
+	metrics.NewCounterFunc("http_panics_total", "Số panic handler đã bắt được.",
+		func() float64 { return float64(panicsTotal.Value()) })
+}
+
+// Metrics đếm request và đo thời gian xử lý theo route pattern (ví dụ
+// /user/{id}) thay vì path thật để số chuỗi label không tăng theo dữ liệu.
+// Request không khớp route nào được gộp vào route "unmatched".
+func Metrics() Middleware {
+	inFlight := httpRequestsInFlight.With()
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			inFlight.Inc()
+			defer inFlight.Dec()
+
+			r = WithRouteSlot(r)
+			rec := &responseRecorder{ResponseWriter: w}
+			next.ServeHTTP(rec, r)
+
+			status := rec.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			method := metricMethod(r.Method)
+			route := metricRoute(RoutePattern(r))
+			httpRequestsTotal.With(method, route, strconv.Itoa(status)).Inc()
+			httpRequestDuration.With(method, route).Observe(time.Since(start).Seconds())
+		})
+	}
+}
+
+// metricRoute bỏ phần method của pattern ("GET /user/{id}" → "/user/{id}")
+func metricRoute(pattern string) string {
+	if pattern == "" {
+		return "unmatched"
+	}
+	if _, path, ok := strings.Cut(pattern, " "); ok {
+		return path
+	}
+	return pattern
+}
+
+// metricMethod gộp các method lạ để client không tạo được label tùy ý
+func metricMethod(m string) string {
+	switch m {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+		http.MethodDelete, http.MethodOptions:
+		return m
+	}
+	return "OTHER"
+}
@@ -0,0 +1,175 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"expvar"
+	"fmt"
+	"net/http"
+	"os"
+	"runtime/debug"
+	"time"
+
+	"vadilatorgolang/package/apperr"
+	"vadilatorgolang/package/logger"
+	"vadilatorgolang/package/requestid"
+)
+
+// Middleware bọc một handler để xử lý trước/sau nó
+type Middleware func(http.Handler) http.Handler
+
+// Chain gộp nhiều middleware thành một; middleware đứng trước nằm ngoài cùng
+// (chạy trước khi request vào và sau cùng khi response ra)
+func Chain(mws ...Middleware) Middleware {
+	return func(h http.Handler) http.Handler {
+		for i := len(mws) - 1; i >= 0; i-- {
+			h = mws[i](h)
+		}
+		return h
+	}
+}
+
+var (
+	ErrBodyTooLarge = apperr.TooLarge("body_too_large", "Body của request vượt quá kích thước cho phép")
+	ErrPanic        = apperr.Internal("internal_error", "Lỗi hệ thống, vui lòng thử lại sau")
+)
+
+// maxRequestIDLen giới hạn request ID nhận từ client để không làm phình log
+const maxRequestIDLen = 128
+
+// RequestID lấy request ID từ header X-Request-ID (nếu hợp lệ) hoặc sinh mới,
+// gắn vào context và trả lại trong header của response
+func RequestID() Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := r.Header.Get(requestid.Header)
+			if !validRequestID(id) {
+				id = requestid.New()
+			}
+			w.Header().Set(requestid.Header, id)
+			next.ServeHTTP(w, r.WithContext(requestid.NewContext(r.Context(), id)))
+		})
+	}
+}
+
+// validRequestID chỉ nhận ký tự in được, không khoảng trắng, tránh chèn dòng vào log
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLen {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// panicsTotal đếm số panic đã bắt được, đọc qua expvar (/debug/vars)
+var panicsTotal = expvar.NewInt("http_panics_total")
+
+// Recover bắt panic trong handler. Mỗi panic có một incident ID được ghi
+// cùng stack trace vào log lỗi và trả cho client trong body JSON 500, để
+// đối chiếu khi có người báo lỗi. Nếu handler đã gửi header thì không thể
+// đổi status nữa, kết nối bị cắt.
+//
+// crashOnPanic (dành cho môi trường dev) in stack ra stderr rồi thoát tiến
+// trình ngay để lỗi không bị bỏ qua. http.ErrAbortHandler luôn được panic
+// lại để net/http cắt kết nối như mong đợi.
+func Recover(crashOnPanic bool) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rec := &responseRecorder{ResponseWriter: w}
+			defer func() {
+				v := recover()
+				if v == nil {
+					return
+				}
+				if v == http.ErrAbortHandler {
+					panic(v)
+				}
+				panicsTotal.Add(1)
+
+				id := newIncidentID()
+				stack := debug.Stack()
+				logger.FromRequest(r).Error("Panic khi xử lý request",
+					"incident_id", id, "route", RoutePattern(r), "panic", v, "stack", string(stack))
+
+				if crashOnPanic {
+					fmt.Fprintf(os.Stderr, "panic: %v [incident %s]\n\n%s", v, id, stack)
+					os.Exit(2)
+				}
+				if rec.status != 0 {
+					panic(http.ErrAbortHandler)
+				}
+				p := apperr.NewProblem(r, ErrPanic)
+				p.IncidentID = id
+				p.Write(w)
+			}()
+			next.ServeHTTP(rec, r)
+		})
+	}
+}
+
+// newIncidentID sinh mã sự cố ngắn, đủ để tìm trong log lỗi
+func newIncidentID() string {
+	var b [8]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+type clientIPKey struct{}
+
+// RealIP xác định IP thật của client theo trusted (xem TrustedProxies.ClientIP)
+// và gắn vào context; đọc lại bằng ClientIP. RemoteAddr được giữ nguyên.
+func RealIP(trusted TrustedProxies) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), clientIPKey{}, trusted.ClientIP(r))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// ClientIP trả về IP do RealIP xác định, nếu không có thì lấy từ RemoteAddr
+func ClientIP(r *http.Request) string {
+	if ip, ok := r.Context().Value(clientIPKey{}).(string); ok {
+		return ip
+	}
+	return remoteIP(r.RemoteAddr)
+}
+
+// Timeout gắn hạn d vào context của request. Handler và repo tự dừng khi
+// ctx hết hạn và trả 504 qua apperr; response không bị bộ đệm nên streaming
+// vẫn hoạt động. d <= 0 thì không làm gì.
+func Timeout(d time.Duration) Middleware {
+	return func(next http.Handler) http.Handler {
+		if d <= 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// BodyLimit từ chối ngay với 413 khi Content-Length vượt n, và giới hạn số
+// byte đọc được từ body với các request không khai báo độ dài. n <= 0 thì không làm gì.
+func BodyLimit(n int64) Middleware {
+	return func(next http.Handler) http.Handler {
+		if n <= 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ContentLength > n {
+				apperr.WriteProblem(w, r, ErrBodyTooLarge)
+				return
+			}
+			r.Body = http.MaxBytesReader(w, r.Body, n)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package server
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"vadilatorgolang/package/apperr"
+	"vadilatorgolang/package/config"
+	"vadilatorgolang/package/logger"
+	"vadilatorgolang/package/ratelimit"
+)
+
+// ErrRateLimited trả về khi client vượt quá giới hạn tần suất
+var ErrRateLimited = apperr.TooManyRequests("rate_limited", "Quá nhiều request, vui lòng thử lại sau")
+
+// KeyFunc trả về khóa nhận diện client để đếm giới hạn
+type KeyFunc func(r *http.Request) string
+
+// KeyByIP nhận diện client theo IP thật (xem RealIP)
+func KeyByIP(r *http.Request) string {
+	return "ip:" + ClientIP(r)
+}
+
+// KeyByAPIKey nhận diện client theo actor của API key trong header. Chỉ key
+// có trong keys mới được tin; thiếu hoặc sai key thì theo IP, để client không
+// lấy được bucket mới chỉ bằng cách đổi key ngẫu nhiên.
+func KeyByAPIKey(header string, keys APIKeys) KeyFunc {
+	return func(r *http.Request) string {
+		if v := r.Header.Get(header); v != "" {
+			if actor, ok := keys.Lookup(v); ok {
+				return "key:" + actor
+			}
+		}
+		return KeyByIP(r)
+	}
+}
+
+// RateRule là giới hạn áp cho một nhóm request cùng cách nhận diện client
+type RateRule struct {
+	Limit ratelimit.Limit
+	Key   KeyFunc
+}
+
+// RateLimiter chọn RateRule theo route của request (Routes, nếu không có thì
+// Default) rồi lấy token từ Store. Route được quy về LogicalRoute, nên
+// /api/v1/users, /api/v2/users, /api/users và /user dùng chung quy tắc và
+// chung bucket; mỗi route có giới hạn riêng dùng bucket riêng.
+type RateLimiter struct {
+	Store   ratelimit.Store
+	Default RateRule
+	// Routes theo LogicalRoute, ví dụ "POST /users"
+	Routes map[string]RateRule
+	// Exempt là các route (theo LogicalRoute) không bị giới hạn, như probe
+	// và /metrics: chúng không được dùng chung bucket "*" với API
+	Exempt map[string]bool
+	// Route tìm pattern sẽ xử lý request, thường là (*Router).Route
+	Route func(*http.Request) string
+}
+
+// NewRateLimiter dựng RateLimiter từ cấu hình; apiKey dùng cho key = api_key
+// (thường là KeyByAPIKey). Các route trong exempt không bị giới hạn.
+func NewRateLimiter(cfg config.RateLimitConfig, apiKey KeyFunc, store ratelimit.Store, route func(*http.Request) string, exempt ...string) *RateLimiter {
+	l := &RateLimiter{
+		Store: store,
+		Default: RateRule{
+			Limit: ratelimit.Limit{Rate: cfg.Rate, Period: cfg.Period, Burst: cfg.Burst},
+			Key:   keyFunc(cfg.Key, apiKey),
+		},
+		Routes: map[string]RateRule{},
+		Exempt: map[string]bool{},
+		Route:  route,
+	}
+	for _, pattern := range exempt {
+		l.Exempt[LogicalRoute(pattern)] = true
+	}
+	for _, rc := range cfg.Routes {
+		key := l.Default.Key
+		if rc.Key != "" {
+			key = keyFunc(rc.Key, apiKey)
+		}
+		l.Routes[LogicalRoute(rc.Route)] = RateRule{
+			Limit: ratelimit.Limit{Rate: rc.Rate, Period: rc.Period, Burst: rc.Burst},
+			Key:   key,
+		}
+	}
+	return l
+}
+
+func keyFunc(kind string, apiKey KeyFunc) KeyFunc {
+	if kind == "api_key" && apiKey != nil {
+		return apiKey
+	}
+	return KeyByIP
+}
+
+// Middleware trả 429 kèm Retry-After khi hết token. Mọi response đi qua đều
+// có RateLimit-Limit/Remaining/Reset. Store lỗi thì cho request đi qua (fail open)
+// để sự cố của store không làm sập cả API.
+func (l *RateLimiter) Middleware() Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route := ""
+			if l.Route != nil {
+				route = LogicalRoute(l.Route(r))
+			}
+			if l.Exempt[route] {
+				next.ServeHTTP(w, r)
+				return
+			}
+			rule, ok := l.Routes[route]
+			if !ok {
+				rule, route = l.Default, "*"
+			}
+			if rule.Limit.Unlimited() {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			res, err := l.Store.Allow(r.Context(), route+"|"+rule.Key(r), rule.Limit)
+			if err != nil {
+				logger.FromRequest(r).Warn("Không thể kiểm tra giới hạn tần suất", "error", err)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			h := w.Header()
+			h.Set("RateLimit-Policy", rule.Limit.String())
+			h.Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+			h.Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+			h.Set("RateLimit-Reset", ceilSeconds(res.Reset))
+			if !res.Allowed {
+				h.Set("Retry-After", ceilSeconds(res.RetryAfter))
+				logger.FromRequest(r).Warn("Vượt giới hạn tần suất", "route", route, "retry_after", res.RetryAfter)
+				apperr.WriteProblem(w, r, ErrRateLimited)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ceilSeconds làm tròn lên theo giây như các header yêu cầu
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,114 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"vadilatorgolang/package/config"
+	"vadilatorgolang/package/ratelimit"
+)
+
+func TestLogicalRoute(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    string
+	}{
+		{"POST /api/v1/users", "POST /users"},
+		{"POST /api/v2/users", "POST /users"},
+		{"POST /api/users", "POST /users"},
+		{"POST /user", "POST /users"},
+		{"GET /user/{id}/audit", "GET /users/{id}/audit"},
+		{"GET /api/v1/users/{id}", "GET /users/{id}"},
+		{"GET /audit", "GET /audit"},
+		{"GET /api/v1/audit", "GET /audit"},
+		{"GET /users", "GET /users"},
+		{"GET /userinfo", "GET /userinfo"},
+		{"GET /apix", "GET /apix"},
+		{"GET /api/version", "GET /version"},
+		{"/api/v1/users", "/users"},
+		{"GET /livez", "GET /livez"},
+		{"GET /api", "GET /"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := LogicalRoute(tt.pattern); got != tt.want {
+			t.Errorf("LogicalRoute(%q) = %q, muốn %q", tt.pattern, got, tt.want)
+		}
+	}
+}
+
+func TestRateLimiterSharesAliases(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	mux := NewMux()
+	for _, prefix := range []string{"/api/v1", "/api/v2", "/api"} {
+		g := mux.Group(prefix)
+		g.Handle("POST /users", ok)
+		g.Handle("GET /users", ok)
+	}
+	mux.Handle("POST /user", ok)
+
+	cfg := config.RateLimitConfig{
+		Key: "ip",
+		// Route viết theo pattern cũ vẫn được quy về route logic
+		Routes: []config.RouteRateLimit{{Route: "POST /api/v1/users", Rate: 2, Period: time.Minute, Burst: 2}},
+	}
+	limiter := NewRateLimiter(cfg, nil, ratelimit.NewMemoryStore(), mux.Route)
+	mux.Use(limiter.Middleware())
+
+	do := func(method, path string) int {
+		r := httptest.NewRequest(method, path, nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		return w.Code
+	}
+	for i, path := range []string{"/api/v1/users", "/api/users"} {
+		if code := do(http.MethodPost, path); code != http.StatusOK {
+			t.Fatalf("request %d tới %s: status = %d, muốn 200", i+1, path, code)
+		}
+	}
+	for _, path := range []string{"/api/v2/users", "/api/users", "/user"} {
+		if code := do(http.MethodPost, path); code != http.StatusTooManyRequests {
+			t.Errorf("POST %s sau khi hết quota: status = %d, muốn 429", path, code)
+		}
+	}
+	// Default không giới hạn, GET không bị ảnh hưởng
+	if code := do(http.MethodGet, "/api/users"); code != http.StatusOK {
+		t.Errorf("GET /api/users: status = %d, muốn 200", code)
+	}
+}
+
+func TestRateLimiterExempt(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	mux := NewMux()
+	mux.Handle("GET /users", ok)
+	mux.Handle("GET /livez", ok)
+	mux.Handle("GET /metrics", ok)
+
+	cfg := config.RateLimitConfig{Key: "ip", Rate: 1, Period: time.Minute, Burst: 1}
+	limiter := NewRateLimiter(cfg, nil, ratelimit.NewMemoryStore(), mux.Route, "GET /livez", "GET /metrics")
+	mux.Use(limiter.Middleware())
+
+	do := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+	if w := do("/users"); w.Code != http.StatusOK {
+		t.Fatalf("GET /users lần đầu: status = %d, muốn 200", w.Code)
+	}
+	if w := do("/users"); w.Code != http.StatusTooManyRequests {
+		t.Fatalf("GET /users khi hết quota: status = %d, muốn 429", w.Code)
+	}
+	// Bucket "*" đã hết nhưng probe và /metrics vẫn đi qua, không có header RateLimit
+	for _, path := range []string{"/livez", "/livez", "/metrics", "/metrics"} {
+		w := do(path)
+		if w.Code != http.StatusOK {
+			t.Errorf("GET %s: status = %d, muốn 200", path, w.Code)
+		}
+		if h := w.Header().Get("RateLimit-Limit"); h != "" {
+			t.Errorf("GET %s: RateLimit-Limit = %q, muốn rỗng", path, h)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package server
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// TrustedProxies là danh sách dải IP của proxy/load balancer được tin cậy.
+// Chỉ khi request đến từ một proxy tin cậy thì X-Forwarded-For mới được dùng.
+type TrustedProxies []*net.IPNet
+
+// ParseTrustedProxies đọc danh sách CIDR hoặc IP đơn ("10.0.0.0/8", "127.0.0.1")
+func ParseTrustedProxies(list []string) (TrustedProxies, error) {
+	var t TrustedProxies
+	for _, s := range list {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		if !strings.Contains(s, "/") {
+			if ip := net.ParseIP(s); ip != nil && ip.To4() != nil {
+				s += "/32"
+			} else {
+				s += "/128"
+			}
+		}
+		_, n, err := net.ParseCIDR(s)
+		if err != nil {
+			return nil, fmt.Errorf("trusted proxy không hợp lệ %q: %w", s, err)
+		}
+		t = append(t, n)
+	}
+	return t, nil
+}
+
+func (t TrustedProxies) contains(ip net.IP) bool {
+	for _, n := range t {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientIP trả về IP thật của client. Nếu kết nối đến từ proxy tin cậy,
+// X-Forwarded-For được duyệt từ phải sang trái và IP đầu tiên không thuộc
+// proxy tin cậy được chọn; giá trị do client tự đặt ở đầu chuỗi bị bỏ qua.
+func (t TrustedProxies) ClientIP(r *http.Request) string {
+	remote := remoteIP(r.RemoteAddr)
+	ip := net.ParseIP(remote)
+	if ip == nil || !t.contains(ip) {
+		return remote
+	}
+
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		hopIP := net.ParseIP(hop)
+		if hopIP == nil {
+			break
+		}
+		if !t.contains(hopIP) {
+			return hop
+		}
+		remote = hop
+	}
+	return remote
+}
+
+func remoteIP(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	return host
+}
@@ -1,29 +1,230 @@
 package server
 
 import (
+	"context"
 	"net/http"
+	"strings"
+
+	"vadilatorgolang/internal/audit"
 	"vadilatorgolang/internal/user" // Import package user
+	"vadilatorgolang/package/config"
 )
 
-// NewRouter khởi tạo và trả về *http.ServeMux đã cấu hình
-func NewRouter(userHandler *user.UserHandler) *http.ServeMux {
+// Router bọc http.ServeMux với ba tầng middleware:
+//   - global (Use trên router gốc): chạy cho mọi request, kể cả 404/405
+//   - group (Group/Use trên group): chạy cho các route đăng ký qua group đó
+//   - route (tham số mw của Handle/HandleFunc): chỉ cho route đó
+//
+// Middleware của group được gắn lúc đăng ký route, nên Use trên group phải
+// gọi trước khi đăng ký route của group.
+type Router struct {
+	root   *rootRouter
+	group  bool
+	prefix string
+	chain  []Middleware
+}
+
+// rootRouter là phần dùng chung giữa router gốc và các group. Chuỗi global
+// được dựng lại mỗi lần Use, nên Use phải xong trước khi server nhận request.
+type rootRouter struct {
+	mux     *http.ServeMux
+	global  []Middleware
+	handler http.Handler
+}
+
+// NewMux tạo Router rỗng
+func NewMux() *Router {
 	mux := http.NewServeMux()
+	return &Router{root: &rootRouter{mux: mux, handler: mux}}
+}
+
+// Use thêm middleware vào chuỗi global (router gốc) hoặc chuỗi của group
+func (r *Router) Use(mws ...Middleware) {
+	if !r.group {
+		r.root.global = append(r.root.global, mws...)
+		r.root.handler = Chain(r.root.global...)(r.root.mux)
+		return
+	}
+	r.chain = append(r.chain, mws...)
+}
 
-	// Đăng ký route cho User
-	// CÁC ROUTE KHÔNG CÓ ID
-	mux.HandleFunc("POST /user", userHandler.CreateUserHandler)
-	mux.HandleFunc("GET /user", userHandler.GetAllUserHandler)
+// Group tạo group con với prefix (ví dụ "/api/v1") và chuỗi middleware riêng,
+// nối tiếp sau prefix và middleware của group cha
+func (r *Router) Group(prefix string, mws ...Middleware) *Router {
+	return &Router{
+		root:   r.root,
+		group:  true,
+		prefix: joinPath(r.prefix, prefix),
+		chain:  append(r.chain[:len(r.chain):len(r.chain)], mws...),
+	}
+}
+
+// Handle đăng ký h cho pattern theo cú pháp của ServeMux ("GET /user/{id}"),
+// path được nối sau prefix của group
+func (r *Router) Handle(pattern string, h http.Handler, mws ...Middleware) {
+	method, path, ok := strings.Cut(pattern, " ")
+	if !ok {
+		method, path = "", pattern
+	}
+	pattern = joinPath(r.prefix, strings.TrimLeft(path, " "))
+	if method != "" {
+		pattern = method + " " + pattern
+	}
+	r.root.mux.Handle(pattern, recordPattern(Chain(r.chain...)(Chain(mws...)(h))))
+}
+
+// HandleFunc giống Handle cho http.HandlerFunc
+func (r *Router) HandleFunc(pattern string, h http.HandlerFunc, mws ...Middleware) {
+	r.Handle(pattern, h, mws...)
+}
 
-	
-	// 'GET /user/get/123'
-	mux.HandleFunc("GET /user/{id}", userHandler.GetUserByIDHandler)
+// Route trả về pattern mà ServeMux sẽ chọn cho req, "" nếu không khớp route
+// nào. Dùng được ở middleware global, trước khi ServeMux xử lý request.
+func (r *Router) Route(req *http.Request) string {
+	_, pattern := r.root.mux.Handler(req)
+	return pattern
+}
 
-	// 'PUT /user/update/123'
-	mux.HandleFunc("PUT /user/{id}", userHandler.UpdateUserHandler)
+func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.root.handler.ServeHTTP(w, req)
+}
 
-	// 'DELETE /user/delete/123'
-	mux.HandleFunc("DELETE /user/{id}", userHandler.DeleteUserHandler)
+type routeKey struct{}
+
+// WithRouteSlot chuẩn bị chỗ để route đã khớp ghi lại pattern của nó.
+// Middleware đứng ngoài ServeMux (access log, metrics) gọi hàm này rồi đọc
+// RoutePattern sau khi handler chạy xong: ServeMux chỉ gán r.Pattern lên bản
+// request nó nhận được, còn các middleware giữa chừng có thể đã tạo bản sao.
+func WithRouteSlot(r *http.Request) *http.Request {
+	if _, ok := r.Context().Value(routeKey{}).(*string); ok {
+		return r
+	}
+	return r.WithContext(context.WithValue(r.Context(), routeKey{}, new(string)))
+}
+
+// RoutePattern trả về pattern của route đã khớp, ví dụ "GET /user/{id}"
+func RoutePattern(r *http.Request) string {
+	if r.Pattern != "" {
+		return r.Pattern
+	}
+	if p, ok := r.Context().Value(routeKey{}).(*string); ok {
+		return *p
+	}
+	return ""
+}
+
+func recordPattern(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if p, ok := r.Context().Value(routeKey{}).(*string); ok {
+			*p = r.Pattern
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// joinPath ghép prefix của group với path của route
+func joinPath(prefix, path string) string {
+	if prefix == "" {
+		return path
+	}
+	if path == "" {
+		return prefix
+	}
+	return strings.TrimSuffix(prefix, "/") + "/" + strings.TrimPrefix(path, "/")
+}
+
+// NewRouter khởi tạo Router với các route của ứng dụng:
+//   - /api/v1/..., /api/v2/...: phiên bản cố định theo đường dẫn
+//   - /api/...: phiên bản theo Accept (xem Negotiate), mặc định cfg.DefaultVersion
+//   - /user..., /audit: route cũ, trả như v1 kèm header Deprecation/Sunset/Link
+//
+// auth (thường là APIKeyAuth) chạy cho mọi route trên, để audit log biết ai
+// thực hiện thay đổi.
+func NewRouter(cfg config.APIConfig, auth Middleware, userHandler *user.UserHandler, auditHandler *audit.Handler) *Router {
+	mux := NewMux()
+
+	auditTarget := auditHandler.TargetHandler(user.AuditTarget)
+	routes := []struct {
+		pattern string
+		v1, v2  http.HandlerFunc
+	}{
+		{"POST /users", userHandler.CreateUserHandler, userHandler.CreateUserV2Handler},
+		{"GET /users", userHandler.GetAllUserHandler, userHandler.GetAllUserV2Handler},
+		{"GET /users/{id}", userHandler.GetUserByIDHandler, userHandler.GetUserByIDV2Handler},
+		{"PUT /users/{id}", userHandler.UpdateUserHandler, userHandler.UpdateUserV2Handler},
+		{"DELETE /users/{id}", userHandler.DeleteUserHandler, userHandler.DeleteUserV2Handler},
+		// Audit log giống nhau ở mọi phiên bản
+		{"GET /users/{id}/audit", auditTarget, auditTarget},
+		{"GET /audit", auditHandler.ListHandler, auditHandler.ListHandler},
+	}
+	v1 := mux.Group("/api/v1", Version(1), auth)
+	v2 := mux.Group("/api/v2", Version(2), auth)
+	api := mux.Group("/api", auth)
+	for _, rt := range routes {
+		v1.HandleFunc(rt.pattern, rt.v1)
+		v2.HandleFunc(rt.pattern, rt.v2)
+		api.Handle(rt.pattern, Negotiate(cfg.DefaultVersion, map[int]http.Handler{1: rt.v1, 2: rt.v2}))
+	}
+
+	// Route cũ, giữ cho client chưa chuyển sang /api/v1
+	legacy := mux.Group("", Deprecated(cfg.DeprecatedAt(), cfg.Sunset(), legacySuccessor), auth)
+	legacy.HandleFunc("POST /user", userHandler.CreateUserHandler)
+	legacy.HandleFunc("GET /user", userHandler.GetAllUserHandler)
+	legacy.HandleFunc("GET /user/{id}", userHandler.GetUserByIDHandler)
+	legacy.HandleFunc("PUT /user/{id}", userHandler.UpdateUserHandler)
+	legacy.HandleFunc("DELETE /user/{id}", userHandler.DeleteUserHandler)
+	legacy.HandleFunc("GET /user/{id}/audit", auditTarget)
+	legacy.HandleFunc("GET /audit", auditHandler.ListHandler)
 
 	return mux
 }
 
+// LogicalRoute bỏ phần phiên bản khỏi pattern để mọi alias của cùng một thao
+// tác có chung tên: "POST /api/v2/users", "POST /api/users" và route cũ
+// "POST /user" đều thành "POST /users". Pattern khác giữ nguyên.
+func LogicalRoute(pattern string) string {
+	if pattern == "" {
+		return ""
+	}
+	method, path, ok := strings.Cut(pattern, " ")
+	if !ok {
+		method, path = "", pattern
+	}
+	if rest, ok := strings.CutPrefix(path, "/api"); ok && (rest == "" || rest[0] == '/') {
+		path = rest
+		if seg, tail, _ := strings.Cut(strings.TrimPrefix(rest, "/"), "/"); isVersionSegment(seg) {
+			path = "/" + tail
+		}
+	} else if path == "/user" || strings.HasPrefix(path, "/user/") {
+		path = "/users" + strings.TrimPrefix(path, "/user")
+	}
+	if path == "" {
+		path = "/"
+	}
+	if method == "" {
+		return path
+	}
+	return method + " " + path
+}
+
+// isVersionSegment cho biết seg có dạng v1, v2...
+func isVersionSegment(seg string) bool {
+	if len(seg) < 2 || seg[0] != 'v' {
+		return false
+	}
+	for _, c := range seg[1:] {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// legacySuccessor đổi path của route cũ sang route tương ứng của /api/v1:
+// /user/5 -> /api/v1/users/5, /audit -> /api/v1/audit
+func legacySuccessor(path string) string {
+	if rest, ok := strings.CutPrefix(path, "/user"); ok {
+		return "/api/v1/users" + rest
+	}
+	return "/api/v1" + path
+}
@@ -0,0 +1,46 @@
+package server
+
+import (
+	"net/http"
+
+	"vadilatorgolang/package/logger"
+	"vadilatorgolang/package/tracing"
+)
+
+// Tracing mở span server cho mỗi request, nối tiếp trace của client nếu
+// request mang traceparent. trace_id/span_id được gắn vào logger của request
+// nên mọi dòng log ghi qua logger.FromRequest/FromContext đều có chúng.
+// Span được đặt tên theo route pattern sau khi ServeMux khớp.
+func Tracing(t *tracing.Tracer) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, span := t.StartServer(r, "HTTP "+r.Method,
+				tracing.String("http.request.method", r.Method),
+				tracing.String("url.path", r.URL.Path),
+				tracing.String("user_agent.original", r.UserAgent()),
+			)
+			sc := span.SpanContext()
+			l := logger.FromRequest(r).With("trace_id", sc.TraceID.String(), "span_id", sc.SpanID.String())
+			r = WithRouteSlot(r.WithContext(logger.NewContext(ctx, l)))
+
+			rec := &responseRecorder{ResponseWriter: w}
+			// defer để span vẫn kết thúc khi Recover cho panic đi tiếp (ErrAbortHandler)
+			defer func() {
+				status := rec.status
+				if status == 0 {
+					status = http.StatusOK
+				}
+				if p := RoutePattern(r); p != "" {
+					span.SetName(p)
+					span.SetAttr(tracing.String("http.route", metricRoute(p)))
+				}
+				span.SetAttr(tracing.Int("http.response.status_code", status))
+				if status >= 500 {
+					span.SetStatus(tracing.StatusError, http.StatusText(status))
+				}
+				span.End()
+			}()
+			next.ServeHTTP(rec, r)
+		})
+	}
+}
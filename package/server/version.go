@@ -0,0 +1,128 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"vadilatorgolang/package/apperr"
+)
+
+// VendorMediaType là tiền tố media type theo phiên bản của API, client chọn
+// phiên bản bằng Accept: application/vnd.vadilatorgolang.v2+json. Cách viết
+// application/json; version=2 cũng được chấp nhận.
+const VendorMediaType = "application/vnd.vadilatorgolang"
+
+var ErrUnsupportedVersion = apperr.NotAcceptable("unsupported_api_version", "Phiên bản API không được hỗ trợ")
+
+type versionKey struct{}
+
+// APIVersion trả về phiên bản API đã chọn cho request, 0 nếu route không
+// thuộc API có phiên bản
+func APIVersion(r *http.Request) int {
+	v, _ := r.Context().Value(versionKey{}).(int)
+	return v
+}
+
+// Version gắn phiên bản v vào request và header API-Version của response.
+// Dùng cho group /api/vN.
+func Version(v int) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("API-Version", strconv.Itoa(v))
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), versionKey{}, v)))
+		})
+	}
+}
+
+// Negotiate chọn handler theo phiên bản client yêu cầu trong Accept; không
+// yêu cầu thì dùng def. Phiên bản không có trong handlers trả 406. Client
+// dùng vendor media type thì response JSON cũng mang media type đó.
+func Negotiate(def int, handlers map[int]http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept")
+		v, vendor, err := requestedVersion(r.Header.Values("Accept"))
+		if err != nil {
+			apperr.WriteProblem(w, r, err)
+			return
+		}
+		if v == 0 {
+			v = def
+		}
+		h, ok := handlers[v]
+		if !ok {
+			apperr.WriteProblem(w, r, ErrUnsupportedVersion.Wrap(fmt.Errorf("version = %d", v)))
+			return
+		}
+		if vendor {
+			w = &mediaTypeWriter{ResponseWriter: w, mediaType: fmt.Sprintf("%s.v%d+json", VendorMediaType, v)}
+		}
+		Version(v)(h).ServeHTTP(w, r)
+	})
+}
+
+// requestedVersion đọc phiên bản từ các giá trị Accept, lấy media type đầu
+// tiên có chỉ định phiên bản (bỏ qua q=0). v = 0 nghĩa là không chỉ định.
+func requestedVersion(accept []string) (v int, vendor bool, err error) {
+	for _, value := range accept {
+		for _, part := range strings.Split(value, ",") {
+			mt, params, perr := mime.ParseMediaType(strings.TrimSpace(part))
+			if perr != nil || params["q"] == "0" {
+				continue
+			}
+			raw, ok := params["version"]
+			isVendor := false
+			switch {
+			case strings.HasPrefix(mt, VendorMediaType+".v") && strings.HasSuffix(mt, "+json"):
+				raw, ok = strings.TrimSuffix(strings.TrimPrefix(mt, VendorMediaType+".v"), "+json"), true
+				isVendor = true
+			case mt == VendorMediaType+"+json":
+				isVendor = true
+			case mt != "application/json":
+				ok = false
+			}
+			if !ok {
+				// vendor media type không kèm phiên bản: dùng phiên bản mặc định
+				vendor = vendor || isVendor
+				continue
+			}
+			n, aerr := strconv.Atoi(raw)
+			if aerr != nil || n <= 0 {
+				return 0, false, ErrUnsupportedVersion.Wrap(fmt.Errorf("Accept = %q", part))
+			}
+			return n, isVendor, nil
+		}
+	}
+	return 0, vendor, nil
+}
+
+// mediaTypeWriter đổi Content-Type application/json của response thành
+// vendor media type đã thỏa thuận; problem+json giữ nguyên
+type mediaTypeWriter struct {
+	http.ResponseWriter
+	mediaType   string
+	wroteHeader bool
+}
+
+func (w *mediaTypeWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		if mt, _, _ := mime.ParseMediaType(w.Header().Get("Content-Type")); mt == "application/json" {
+			w.Header().Set("Content-Type", w.mediaType)
+		}
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *mediaTypeWriter) Write(p []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+// Unwrap cho http.ResponseController truy cập writer gốc
+func (w *mediaTypeWriter) Unwrap() http.ResponseWriter { return w.ResponseWriter }
@@ -0,0 +1,96 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestRequestedVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		accept  []string
+		version int
+		vendor  bool
+		err     bool
+	}{
+		{"không có Accept", nil, 0, false, false},
+		{"json thường", []string{"application/json"}, 0, false, false},
+		{"*/*", []string{"*/*"}, 0, false, false},
+		{"vendor v2", []string{"application/vnd.vadilatorgolang.v2+json"}, 2, true, false},
+		{"vendor v1 kèm charset", []string{"application/vnd.vadilatorgolang.v1+json; charset=utf-8"}, 1, true, false},
+		{"vendor không phiên bản", []string{"application/vnd.vadilatorgolang+json"}, 0, true, false},
+		{"vendor kèm tham số version", []string{"application/vnd.vadilatorgolang+json; version=2"}, 2, true, false},
+		{"json version=2", []string{"application/json; version=2"}, 2, false, false},
+		{"lấy media type đầu tiên có phiên bản", []string{"text/html, application/json;version=1, application/vnd.vadilatorgolang.v2+json"}, 1, false, false},
+		{"nhiều header Accept", []string{"text/html", "application/vnd.vadilatorgolang.v2+json"}, 2, true, false},
+		{"bỏ qua q=0", []string{"application/vnd.vadilatorgolang.v2+json;q=0, application/json;version=1"}, 1, false, false},
+		{"vendor không phiên bản rồi json có phiên bản", []string{"application/vnd.vadilatorgolang+json, application/json;version=2"}, 2, false, false},
+		{"version không phải số", []string{"application/json; version=abc"}, 0, false, true},
+		{"version âm", []string{"application/vnd.vadilatorgolang.v-1+json"}, 0, false, true},
+		{"media type hỏng bị bỏ qua", []string{"application/json; version"}, 0, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, vendor, err := requestedVersion(tt.accept)
+			if (err != nil) != tt.err {
+				t.Fatalf("err = %v, muốn lỗi = %v", err, tt.err)
+			}
+			if err != nil && !errors.Is(err, ErrUnsupportedVersion) {
+				t.Errorf("err = %v, muốn ErrUnsupportedVersion", err)
+			}
+			if v != tt.version || vendor != tt.vendor {
+				t.Errorf("= %d, %v; muốn %d, %v", v, vendor, tt.version, tt.vendor)
+			}
+		})
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	handler := func(v int) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]int{"version": APIVersion(r)})
+		})
+	}
+	h := Negotiate(1, map[int]http.Handler{1: handler(1), 2: handler(2)})
+
+	tests := []struct {
+		accept      string
+		status      int
+		version     int
+		contentType string
+	}{
+		{"", 200, 1, "application/json"},
+		{"application/json; version=2", 200, 2, "application/json"},
+		{"application/vnd.vadilatorgolang.v2+json", 200, 2, "application/vnd.vadilatorgolang.v2+json"},
+		{"application/vnd.vadilatorgolang+json", 200, 1, "application/vnd.vadilatorgolang.v1+json"},
+		{"application/vnd.vadilatorgolang.v3+json", 406, 0, "application/problem+json"},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/api/users", nil)
+		if tt.accept != "" {
+			r.Header.Set("Accept", tt.accept)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		if w.Code != tt.status || w.Header().Get("Content-Type") != tt.contentType {
+			t.Errorf("Accept %q: %d %q, muốn %d %q", tt.accept, w.Code, w.Header().Get("Content-Type"), tt.status, tt.contentType)
+		}
+		if w.Header().Get("Vary") != "Accept" {
+			t.Errorf("Accept %q: thiếu Vary: Accept", tt.accept)
+		}
+		if tt.status != 200 {
+			continue
+		}
+		var body map[string]int
+		json.NewDecoder(w.Body).Decode(&body)
+		if body["version"] != tt.version || w.Header().Get("API-Version") != strconv.Itoa(tt.version) {
+			t.Errorf("Accept %q: version = %d, API-Version = %q; muốn %d", tt.accept, body["version"], w.Header().Get("API-Version"), tt.version)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+// Package tracing là tracing tối giản theo W3C Trace Context: nhận và truyền
+// header traceparent/tracestate, tạo span lồng nhau qua context và gửi span
+// đã kết thúc cho một Exporter (JSON ra stdout/file hoặc OTLP/HTTP JSON).
+package tracing
+
+import (
+	"context"
+	"encoding/hex"
+	"net/http"
+	"strings"
+)
+
+// Header của W3C Trace Context
+const (
+	TraceparentHeader = "traceparent"
+	TracestateHeader  = "tracestate"
+)
+
+// maxTracestateLen là độ dài tối đa của tracestate được truyền tiếp
+const maxTracestateLen = 512
+
+// TraceID định danh một trace (16 byte)
+type TraceID [16]byte
+
+// SpanID định danh một span trong trace (8 byte)
+type SpanID [8]byte
+
+func (t TraceID) String() string { return hex.EncodeToString(t[:]) }
+func (t TraceID) IsValid() bool  { return t != TraceID{} }
+func (s SpanID) String() string  { return hex.EncodeToString(s[:]) }
+func (s SpanID) IsValid() bool   { return s != SpanID{} }
+
+// FlagSampled là bit trace-flags báo span được ghi lại
+const FlagSampled byte = 0x01
+
+// SpanContext là phần của span được truyền giữa các service
+type SpanContext struct {
+	TraceID    TraceID
+	SpanID     SpanID
+	Flags      byte
+	TraceState string
+	// Remote = true khi SpanContext đọc từ header của request đến
+	Remote bool
+}
+
+func (sc SpanContext) IsValid() bool   { return sc.TraceID.IsValid() && sc.SpanID.IsValid() }
+func (sc SpanContext) IsSampled() bool { return sc.Flags&FlagSampled != 0 }
+
+// Traceparent định dạng sc thành giá trị header traceparent (version 00)
+func (sc SpanContext) Traceparent() string {
+	return "00-" + sc.TraceID.String() + "-" + sc.SpanID.String() + "-" + hex.EncodeToString([]byte{sc.Flags})
+}
+
+// ParseTraceparent đọc header traceparent. Version lạ (khác ff) vẫn được
+// chấp nhận nếu phần đầu đúng định dạng version 00, như spec yêu cầu.
+func ParseTraceparent(h string) (SpanContext, bool) {
+	h = strings.TrimSpace(h)
+	// 00-<32 hex>-<16 hex>-<2 hex>
+	if len(h) < 55 || h[2] != '-' || h[35] != '-' || h[52] != '-' {
+		return SpanContext{}, false
+	}
+	version := h[:2]
+	if !isLowerHex(version) || version == "ff" {
+		return SpanContext{}, false
+	}
+	if len(h) > 55 && (version == "00" || h[55] != '-') {
+		return SpanContext{}, false
+	}
+
+	var sc SpanContext
+	if !decodeHex(sc.TraceID[:], h[3:35]) || !decodeHex(sc.SpanID[:], h[36:52]) {
+		return SpanContext{}, false
+	}
+	var flags [1]byte
+	if !decodeHex(flags[:], h[53:55]) {
+		return SpanContext{}, false
+	}
+	sc.Flags = flags[0]
+	if !sc.IsValid() {
+		return SpanContext{}, false
+	}
+	sc.Remote = true
+	return sc, true
+}
+
+// decodeHex chỉ nhận hex chữ thường như spec
+func decodeHex(dst []byte, s string) bool {
+	if !isLowerHex(s) {
+		return false
+	}
+	_, err := hex.Decode(dst, []byte(s))
+	return err == nil
+}
+
+func isLowerHex(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// Extract đọc SpanContext từ header của request đến; tracestate chỉ được
+// giữ khi traceparent hợp lệ
+func Extract(h http.Header) (SpanContext, bool) {
+	sc, ok := ParseTraceparent(h.Get(TraceparentHeader))
+	if !ok {
+		return SpanContext{}, false
+	}
+	if ts := strings.Join(h.Values(TracestateHeader), ","); len(ts) <= maxTracestateLen {
+		sc.TraceState = ts
+	}
+	return sc, true
+}
+
+// Inject ghi span hiện tại trong ctx vào header của request gửi đi
+func Inject(ctx context.Context, h http.Header) {
+	sc := SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return
+	}
+	h.Set(TraceparentHeader, sc.Traceparent())
+	if sc.TraceState != "" {
+		h.Set(TracestateHeader, sc.TraceState)
+	}
+}
+
+type spanKey struct{}
+
+// ContextWithSpan gắn span vào ctx
+func ContextWithSpan(ctx context.Context, s *Span) context.Context {
+	return context.WithValue(ctx, spanKey{}, s)
+}
+
+// SpanFromContext lấy span hiện tại trong ctx, nil nếu không có
+func SpanFromContext(ctx context.Context) *Span {
+	s, _ := ctx.Value(spanKey{}).(*Span)
+	return s
+}
+
+// SpanContextFromContext lấy SpanContext của span hiện tại, rỗng nếu không có
+func SpanContextFromContext(ctx context.Context) SpanContext {
+	return SpanFromContext(ctx).SpanContext()
+}
@@ -0,0 +1,76 @@
+package tracing
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	const (
+		traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+		spanID  = "00f067aa0ba902b7"
+	)
+	tests := []struct {
+		name    string
+		header  string
+		ok      bool
+		sampled bool
+	}{
+		{"sampled", "00-" + traceID + "-" + spanID + "-01", true, true},
+		{"không sampled", "00-" + traceID + "-" + spanID + "-00", true, false},
+		{"khoảng trắng", "  00-" + traceID + "-" + spanID + "-01 ", true, true},
+		{"version lạ có phần mở rộng", "cc-" + traceID + "-" + spanID + "-01-what-the-future-holds", true, true},
+		{"version lạ không phần mở rộng", "cc-" + traceID + "-" + spanID + "-01", true, true},
+		{"version 00 có phần thừa", "00-" + traceID + "-" + spanID + "-01-x", false, false},
+		{"version lạ dính liền", "cc-" + traceID + "-" + spanID + "-01x", false, false},
+		{"version ff", "ff-" + traceID + "-" + spanID + "-01", false, false},
+		{"chữ hoa", "00-4BF92F3577B34DA6A3CE929D0E0E4736-" + spanID + "-01", false, false},
+		{"trace id toàn 0", "00-00000000000000000000000000000000-" + spanID + "-01", false, false},
+		{"span id toàn 0", "00-" + traceID + "-0000000000000000-01", false, false},
+		{"thiếu flags", "00-" + traceID + "-" + spanID, false, false},
+		{"sai dấu phân cách", "00_" + traceID + "-" + spanID + "-01", false, false},
+		{"không phải hex", "00-" + traceID + "-" + "00f067aa0ba902bz" + "-01", false, false},
+		{"rỗng", "", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sc, ok := ParseTraceparent(tt.header)
+			if ok != tt.ok {
+				t.Fatalf("ok = %v, muốn %v", ok, tt.ok)
+			}
+			if !ok {
+				return
+			}
+			if sc.TraceID.String() != traceID || sc.SpanID.String() != spanID || !sc.Remote {
+				t.Errorf("sc = %+v", sc)
+			}
+			if sc.IsSampled() != tt.sampled {
+				t.Errorf("IsSampled = %v, muốn %v", sc.IsSampled(), tt.sampled)
+			}
+		})
+	}
+}
+
+func TestTraceparentRoundTrip(t *testing.T) {
+	const h = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	sc, ok := ParseTraceparent(h)
+	if !ok || sc.Traceparent() != h {
+		t.Fatalf("Traceparent() = %q, muốn %q", sc.Traceparent(), h)
+	}
+}
+
+func TestExtractTracestate(t *testing.T) {
+	h := http.Header{}
+	h.Set(TraceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	h.Add(TracestateHeader, "a=1")
+	h.Add(TracestateHeader, "b=2")
+	sc, ok := Extract(h)
+	if !ok || sc.TraceState != "a=1,b=2" {
+		t.Errorf("Extract = %+v, %v", sc, ok)
+	}
+
+	h.Set(TraceparentHeader, "sai")
+	if _, ok := Extract(h); ok {
+		t.Error("traceparent sai mà vẫn Extract được")
+	}
+}
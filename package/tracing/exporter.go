@@ -0,0 +1,279 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"vadilatorgolang/package/config"
+)
+
+// Các exporter có sẵn (TracingConfig.Exporter)
+const (
+	ExporterStdout = "stdout"
+	ExporterFile   = "file"
+	ExporterOTLP   = "otlp"
+)
+
+// Exporter gửi các span đã kết thúc tới nơi lưu trữ
+type Exporter interface {
+	Export(ctx context.Context, spans []SpanData) error
+	Shutdown(ctx context.Context) error
+}
+
+// NewExporter tạo exporter theo cfg.Exporter. File của exporter "file"
+// được mở ở chế độ ghi nối tiếp.
+func NewExporter(cfg config.TracingConfig) (Exporter, error) {
+	switch cfg.Exporter {
+	case ExporterStdout:
+		return NewJSONExporter(os.Stdout, cfg.ServiceName), nil
+	case ExporterFile:
+		return NewFileExporter(cfg.File, cfg.ServiceName)
+	case ExporterOTLP:
+		return NewOTLPExporter(cfg.Endpoint, cfg.Headers, cfg.ServiceName, cfg.Timeout)
+	}
+	return nil, fmt.Errorf("tracing: exporter không hỗ trợ %q", cfg.Exporter)
+}
+
+// JSONExporter ghi mỗi span một dòng JSON
+type JSONExporter struct {
+	mu      sync.Mutex
+	w       io.Writer
+	closer  io.Closer
+	service string
+}
+
+// jsonSpan là dạng JSON của một span
+type jsonSpan struct {
+	Service       string         `json:"service"`
+	TraceID       string         `json:"trace_id"`
+	SpanID        string         `json:"span_id"`
+	ParentSpanID  string         `json:"parent_span_id,omitempty"`
+	Name          string         `json:"name"`
+	Kind          string         `json:"kind"`
+	Start         time.Time      `json:"start"`
+	End           time.Time      `json:"end"`
+	DurationMs    float64        `json:"duration_ms"`
+	Status        string         `json:"status"`
+	StatusMessage string         `json:"status_message,omitempty"`
+	Attributes    map[string]any `json:"attributes,omitempty"`
+}
+
+func NewJSONExporter(w io.Writer, service string) *JSONExporter {
+	return &JSONExporter{w: w, service: service}
+}
+
+// NewFileExporter ghi span vào file path (tạo mới nếu chưa có)
+func NewFileExporter(path, service string) (*JSONExporter, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("tracing: không thể mở file span: %w", err)
+	}
+	return &JSONExporter{w: f, closer: f, service: service}, nil
+}
+
+func (e *JSONExporter) Export(_ context.Context, spans []SpanData) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, s := range spans {
+		js := jsonSpan{
+			Service:       e.service,
+			TraceID:       s.TraceID.String(),
+			SpanID:        s.SpanID.String(),
+			Name:          s.Name,
+			Kind:          s.Kind.String(),
+			Start:         s.Start,
+			End:           s.End,
+			DurationMs:    float64(s.End.Sub(s.Start).Microseconds()) / 1000,
+			Status:        s.Status.String(),
+			StatusMessage: s.StatusMessage,
+		}
+		if s.Parent.IsValid() {
+			js.ParentSpanID = s.Parent.String()
+		}
+		if len(s.Attrs) > 0 {
+			js.Attributes = make(map[string]any, len(s.Attrs))
+			for _, a := range s.Attrs {
+				js.Attributes[a.Key] = a.Value
+			}
+		}
+		if err := enc.Encode(js); err != nil {
+			return err
+		}
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	_, err := e.w.Write(buf.Bytes())
+	return err
+}
+
+func (e *JSONExporter) Shutdown(context.Context) error {
+	if e.closer != nil {
+		return e.closer.Close()
+	}
+	return nil
+}
+
+// OTLPExporter gửi span tới collector OpenTelemetry qua OTLP/HTTP dạng JSON
+// (POST tới endpoint, thường là http://<collector>:4318/v1/traces)
+type OTLPExporter struct {
+	endpoint string
+	headers  map[string]string
+	service  string
+	client   *http.Client
+}
+
+// NewOTLPExporter tạo exporter; headers có dạng "Tên=giá trị", ví dụ để gửi
+// API key của collector
+func NewOTLPExporter(endpoint string, headers []string, service string, timeout time.Duration) (*OTLPExporter, error) {
+	if endpoint == "" {
+		return nil, fmt.Errorf("tracing: thiếu endpoint cho exporter otlp")
+	}
+	h := make(map[string]string, len(headers))
+	for _, kv := range headers {
+		k, v, ok := strings.Cut(kv, "=")
+		if !ok || strings.TrimSpace(k) == "" {
+			return nil, fmt.Errorf("tracing: header OTLP %q phải có dạng Tên=giá trị", kv)
+		}
+		h[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+	return &OTLPExporter{
+		endpoint: endpoint,
+		headers:  h,
+		service:  service,
+		client:   &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// Cấu trúc JSON của OTLP (opentelemetry/proto/trace/v1), chỉ các trường dùng tới.
+// ID viết dạng hex, thời gian là số nano giây dạng chuỗi.
+type (
+	otlpRequest struct {
+		ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+	}
+	otlpResourceSpans struct {
+		Resource   otlpResource     `json:"resource"`
+		ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+	}
+	otlpResource struct {
+		Attributes []otlpKeyValue `json:"attributes"`
+	}
+	otlpScopeSpans struct {
+		Scope otlpScope  `json:"scope"`
+		Spans []otlpSpan `json:"spans"`
+	}
+	otlpScope struct {
+		Name string `json:"name"`
+	}
+	otlpSpan struct {
+		TraceID           string         `json:"traceId"`
+		SpanID            string         `json:"spanId"`
+		ParentSpanID      string         `json:"parentSpanId,omitempty"`
+		TraceState        string         `json:"traceState,omitempty"`
+		Name              string         `json:"name"`
+		Kind              int            `json:"kind"`
+		StartTimeUnixNano string         `json:"startTimeUnixNano"`
+		EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+		Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+		Status            otlpStatus     `json:"status"`
+	}
+	otlpStatus struct {
+		Code    int    `json:"code,omitempty"`
+		Message string `json:"message,omitempty"`
+	}
+	otlpKeyValue struct {
+		Key   string    `json:"key"`
+		Value otlpValue `json:"value"`
+	}
+	otlpValue struct {
+		StringValue *string  `json:"stringValue,omitempty"`
+		BoolValue   *bool    `json:"boolValue,omitempty"`
+		IntValue    *string  `json:"intValue,omitempty"`
+		DoubleValue *float64 `json:"doubleValue,omitempty"`
+	}
+)
+
+func otlpAttr(a Attr) otlpKeyValue {
+	var v otlpValue
+	switch x := a.Value.(type) {
+	case string:
+		v.StringValue = &x
+	case bool:
+		v.BoolValue = &x
+	case int:
+		s := strconv.Itoa(x)
+		v.IntValue = &s
+	case int64:
+		s := strconv.FormatInt(x, 10)
+		v.IntValue = &s
+	case float64:
+		v.DoubleValue = &x
+	default:
+		s := fmt.Sprint(x)
+		v.StringValue = &s
+	}
+	return otlpKeyValue{Key: a.Key, Value: v}
+}
+
+func (e *OTLPExporter) Export(ctx context.Context, spans []SpanData) error {
+	out := make([]otlpSpan, 0, len(spans))
+	for _, s := range spans {
+		sp := otlpSpan{
+			TraceID:           s.TraceID.String(),
+			SpanID:            s.SpanID.String(),
+			TraceState:        s.TraceState,
+			Name:              s.Name,
+			Kind:              int(s.Kind),
+			StartTimeUnixNano: strconv.FormatInt(s.Start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(s.End.UnixNano(), 10),
+			Status:            otlpStatus{Code: int(s.Status), Message: s.StatusMessage},
+		}
+		if s.Parent.IsValid() {
+			sp.ParentSpanID = s.Parent.String()
+		}
+		for _, a := range s.Attrs {
+			sp.Attributes = append(sp.Attributes, otlpAttr(a))
+		}
+		out = append(out, sp)
+	}
+	body, err := json.Marshal(otlpRequest{ResourceSpans: []otlpResourceSpans{{
+		Resource:   otlpResource{Attributes: []otlpKeyValue{otlpAttr(String("service.name", e.service))}},
+		ScopeSpans: []otlpScopeSpans{{Scope: otlpScope{Name: "vadilatorgolang/package/tracing"}, Spans: out}},
+	}}})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range e.headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("tracing: collector trả về %s", resp.Status)
+	}
+	return nil
+}
+
+func (e *OTLPExporter) Shutdown(context.Context) error {
+	e.client.CloseIdleConnections()
+	return nil
+}
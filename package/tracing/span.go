@@ -0,0 +1,215 @@
+package tracing
+
+import (
+	"context"
+	"encoding/binary"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// Kind là vai trò của span theo OpenTelemetry
+type Kind int
+
+const (
+	KindInternal Kind = iota + 1
+	KindServer
+	KindClient
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindServer:
+		return "server"
+	case KindClient:
+		return "client"
+	}
+	return "internal"
+}
+
+// Status là kết quả của span
+type Status int
+
+const (
+	StatusUnset Status = iota
+	StatusOK
+	StatusError
+)
+
+func (s Status) String() string {
+	switch s {
+	case StatusOK:
+		return "ok"
+	case StatusError:
+		return "error"
+	}
+	return "unset"
+}
+
+// Attr là một thuộc tính của span; Value là string, bool, int, int64 hoặc float64
+type Attr struct {
+	Key   string
+	Value any
+}
+
+func String(k, v string) Attr          { return Attr{k, v} }
+func Int(k string, v int) Attr         { return Attr{k, v} }
+func Int64(k string, v int64) Attr     { return Attr{k, v} }
+func Bool(k string, v bool) Attr       { return Attr{k, v} }
+func Float64(k string, v float64) Attr { return Attr{k, v} }
+
+// Span là một đoạn công việc có thời điểm bắt đầu/kết thúc. Mọi method an
+// toàn với span nil, nên code được trace không cần kiểm tra tracing có bật.
+type Span struct {
+	tracer *Tracer
+	sc     SpanContext
+	parent SpanID
+	kind   Kind
+	start  time.Time
+
+	mu        sync.Mutex
+	name      string
+	attrs     []Attr
+	status    Status
+	statusMsg string
+	ended     bool
+}
+
+// SpanData là bản chụp của span đã kết thúc, được gửi cho Exporter
+type SpanData struct {
+	SpanContext
+	Parent        SpanID
+	Name          string
+	Kind          Kind
+	Start, End    time.Time
+	Attrs         []Attr
+	Status        Status
+	StatusMessage string
+}
+
+// Start mở span con của span hiện tại trong ctx. Không có span cha (request
+// không đi qua middleware tracing, lệnh CLI...) hoặc span cha không được
+// sample thì trả về ctx như cũ và span nil.
+func Start(ctx context.Context, name string, attrs ...Attr) (context.Context, *Span) {
+	parent := SpanFromContext(ctx)
+	if parent == nil || !parent.sc.IsSampled() {
+		return ctx, nil
+	}
+	s := parent.tracer.newSpan(name, KindInternal, parent.sc, attrs)
+	return ContextWithSpan(ctx, s), s
+}
+
+// StartClient giống Start cho lời gọi ra ngoài (database, HTTP)
+func StartClient(ctx context.Context, name string, attrs ...Attr) (context.Context, *Span) {
+	ctx, s := Start(ctx, name, attrs...)
+	if s != nil {
+		s.kind = KindClient
+	}
+	return ctx, s
+}
+
+func newSpanID() SpanID {
+	var id SpanID
+	for !id.IsValid() {
+		binary.BigEndian.PutUint64(id[:], rand.Uint64())
+	}
+	return id
+}
+
+func newTraceID() TraceID {
+	var id TraceID
+	for !id.IsValid() {
+		binary.BigEndian.PutUint64(id[:8], rand.Uint64())
+		binary.BigEndian.PutUint64(id[8:], rand.Uint64())
+	}
+	return id
+}
+
+// SpanContext trả về định danh của span (rỗng với span nil)
+func (s *Span) SpanContext() SpanContext {
+	if s == nil {
+		return SpanContext{}
+	}
+	return s.sc
+}
+
+// SetName đổi tên span, ví dụ khi route chỉ biết sau khi ServeMux khớp
+func (s *Span) SetName(name string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.name = name
+	s.mu.Unlock()
+}
+
+// SetAttr thêm thuộc tính
+func (s *Span) SetAttr(attrs ...Attr) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.attrs = append(s.attrs, attrs...)
+	s.mu.Unlock()
+}
+
+// SetStatus đặt kết quả của span
+func (s *Span) SetStatus(status Status, msg string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.status, s.statusMsg = status, msg
+	s.mu.Unlock()
+}
+
+// SetError đánh dấu span lỗi với err; err nil thì bỏ qua
+func (s *Span) SetError(err error) {
+	if err == nil {
+		return
+	}
+	s.SetStatus(StatusError, err.Error())
+}
+
+// End kết thúc span và gửi cho exporter nếu được sample. Gọi lại lần nữa
+// không có tác dụng.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	end := time.Now()
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	d := SpanData{
+		SpanContext:   s.sc,
+		Parent:        s.parent,
+		Name:          s.name,
+		Kind:          s.kind,
+		Start:         s.start,
+		End:           end,
+		Attrs:         s.attrs,
+		Status:        s.status,
+		StatusMessage: s.statusMsg,
+	}
+	s.mu.Unlock()
+
+	if s.sc.IsSampled() {
+		s.tracer.enqueue(d)
+	}
+}
+
+// Finish là End kèm ghi lỗi, dùng với defer và kết quả có tên:
+//
+//	func f(ctx context.Context) (err error) {
+//		ctx, span := tracing.Start(ctx, "f")
+//		defer span.Finish(&err)
+func (s *Span) Finish(errp *error) {
+	if errp != nil {
+		s.SetError(*errp)
+	}
+	s.End()
+}
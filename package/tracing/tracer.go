@@ -0,0 +1,164 @@
+package tracing
+
+import (
+	"context"
+	"encoding/binary"
+	"math"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"vadilatorgolang/package/config"
+	"vadilatorgolang/package/logger"
+)
+
+// queueSize là số span tối đa chờ export; hàng đợi đầy thì span bị bỏ
+const queueSize = 4096
+
+// Tracer tạo span gốc cho request và gom span đã kết thúc thành lô để
+// export ở goroutine nền, nên việc export không làm chậm request.
+type Tracer struct {
+	exporter Exporter
+	ratio    float64
+	batch    int
+	interval time.Duration
+
+	mu      sync.RWMutex // giữ khi gửi vào queue; Shutdown lấy Lock để đóng
+	closed  bool
+	queue   chan SpanData
+	done    chan struct{}
+	dropped atomic.Int64
+}
+
+// New tạo Tracer gửi span cho exp theo cấu hình lô và tỉ lệ sample của cfg
+func New(cfg config.TracingConfig, exp Exporter) *Tracer {
+	t := &Tracer{
+		exporter: exp,
+		ratio:    cfg.SampleRatio,
+		batch:    max(cfg.BatchSize, 1),
+		interval: cfg.FlushInterval,
+		queue:    make(chan SpanData, queueSize),
+		done:     make(chan struct{}),
+	}
+	if t.interval <= 0 {
+		t.interval = 5 * time.Second
+	}
+	go t.run()
+	return t
+}
+
+// StartServer mở span gốc cho request r. Nếu r mang traceparent hợp lệ thì
+// span là con của span phía client và theo quyết định sample của nó; nếu
+// không thì bắt đầu trace mới, sample theo SampleRatio. Span luôn có ID
+// (để ghi vào log) kể cả khi không được sample.
+func (t *Tracer) StartServer(r *http.Request, name string, attrs ...Attr) (context.Context, *Span) {
+	parent, ok := Extract(r.Header)
+	if !ok {
+		parent = SpanContext{TraceID: newTraceID()}
+		if t.sample(parent.TraceID) {
+			parent.Flags = FlagSampled
+		}
+	}
+	s := t.newSpan(name, KindServer, parent, attrs)
+	return ContextWithSpan(r.Context(), s), s
+}
+
+func (t *Tracer) newSpan(name string, kind Kind, parent SpanContext, attrs []Attr) *Span {
+	return &Span{
+		tracer: t,
+		sc: SpanContext{
+			TraceID:    parent.TraceID,
+			SpanID:     newSpanID(),
+			Flags:      parent.Flags,
+			TraceState: parent.TraceState,
+		},
+		parent: parent.SpanID,
+		kind:   kind,
+		start:  time.Now(),
+		name:   name,
+		attrs:  attrs,
+	}
+}
+
+// sample quyết định theo trace ID nên mọi service dùng cùng tỉ lệ sẽ cho
+// cùng kết quả với một trace
+func (t *Tracer) sample(id TraceID) bool {
+	switch {
+	case t.ratio >= 1:
+		return true
+	case t.ratio <= 0:
+		return false
+	}
+	return binary.BigEndian.Uint64(id[8:]) < uint64(t.ratio*math.MaxUint64)
+}
+
+func (t *Tracer) enqueue(d SpanData) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.closed {
+		t.dropped.Add(1)
+		return
+	}
+	select {
+	case t.queue <- d:
+	default:
+		t.dropped.Add(1)
+	}
+}
+
+func (t *Tracer) run() {
+	defer close(t.done)
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+
+	batch := make([]SpanData, 0, t.batch)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), t.interval)
+		if err := t.exporter.Export(ctx, batch); err != nil {
+			logger.WarnLogger.Printf("Không thể export %d span: %v", len(batch), err)
+		}
+		cancel()
+		// exporter có thể còn giữ batch cũ nên cấp slice mới
+		batch = make([]SpanData, 0, t.batch)
+	}
+
+	for {
+		select {
+		case d, ok := <-t.queue:
+			if !ok {
+				flush()
+				return
+			}
+			batch = append(batch, d)
+			if len(batch) >= t.batch {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// Shutdown ngừng nhận span, export nốt các span đang chờ rồi đóng exporter
+func (t *Tracer) Shutdown(ctx context.Context) error {
+	t.mu.Lock()
+	if !t.closed {
+		t.closed = true
+		close(t.queue)
+	}
+	t.mu.Unlock()
+
+	select {
+	case <-t.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	if n := t.dropped.Load(); n > 0 {
+		logger.WarnLogger.Printf("Đã bỏ %d span do hàng đợi export đầy", n)
+	}
+	return t.exporter.Shutdown(ctx)
+}
@@ -1,16 +1,46 @@
 package validator
 
 import (
+	"errors"
+	"fmt"
+	"reflect"
 	"regexp"
+	"strings"
+
+	"vadilatorgolang/package/apperr"
+	"vadilatorgolang/package/metrics"
 
 	"github.com/go-playground/validator/v10"
 )
 
 var (
-	validate      = validator.New()
+	validate      = newValidate()
 	usernameRegex = regexp.MustCompile("^[a-zA-Z0-9_]+$")
+
+	// failuresTotal đếm lỗi validate của request theo trường và tag
+	failuresTotal = metrics.NewCounterVec("validation_failures_total",
+		"Số lỗi validate theo trường và tag.", "field", "tag")
 )
 
+// newValidate tạo validator báo lỗi theo tên JSON/YAML của trường
+// (user_name) thay vì tên trong Go (UserName). Trường lấy từ path/query/header
+// (xem package/bind) được báo theo tên trong tag tương ứng.
+func newValidate() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		for _, key := range []string{"json", "yaml", "path", "query", "header"} {
+			name := strings.SplitN(f.Tag.Get(key), ",", 2)[0]
+			if name == "-" {
+				continue
+			}
+			if name != "" {
+				return name
+			}
+		}
+		return f.Name
+	})
+	return v
+}
 
 func RegisterCustomValidations() {
 	err := validate.RegisterValidation("username_chars", validateUsernameChars)
@@ -32,3 +62,63 @@ func validateUsernameChars(fl validator.FieldLevel) bool {
 func ValidateStruct(s interface{}) error {
 	return validate.Struct(s)
 }
+
+// FieldErrors chuyển lỗi của ValidateStruct thành danh sách lỗi theo trường.
+// ok = false nếu err không phải lỗi validate. Mỗi lỗi được đếm vào metric
+// validation_failures_total.
+func FieldErrors(err error) (fields []apperr.FieldError, ok bool) {
+	var ve validator.ValidationErrors
+	if !errors.As(err, &ve) {
+		return nil, false
+	}
+	for _, e := range ve {
+		failuresTotal.With(e.Field(), e.Tag()).Inc()
+		fields = append(fields, apperr.FieldError{
+			Field:   e.Field(),
+			Rule:    e.Tag(),
+			Param:   e.Param(),
+			Message: message(e),
+		})
+	}
+	return fields, true
+}
+
+// ErrValidation là lỗi trả về khi dữ liệu vi phạm tag validate; chi tiết
+// từng trường nằm trong Fields
+var ErrValidation = apperr.Validation("validation_failed", "Dữ liệu không hợp lệ")
+
+// ToAppError bọc lỗi validate thành ErrValidation với chi tiết từng trường
+func ToAppError(err error) *apperr.Error {
+	if fields, ok := FieldErrors(err); ok {
+		return ErrValidation.WithFields(fields).Wrap(err)
+	}
+	return ErrValidation.Wrap(err)
+}
+
+// message sinh thông báo cho người dùng theo quy tắc bị vi phạm
+func message(e validator.FieldError) string {
+	switch e.Tag() {
+	case "gt":
+		return fmt.Sprintf("Trường '%s' phải lớn hơn %s", e.Field(), e.Param())
+	case "lt":
+		return fmt.Sprintf("Trường '%s' phải nhỏ hơn %s", e.Field(), e.Param())
+	case "gte":
+		return fmt.Sprintf("Trường '%s' phải lớn hơn hoặc bằng %s", e.Field(), e.Param())
+	case "lte":
+		return fmt.Sprintf("Trường '%s' phải nhỏ hơn hoặc bằng %s", e.Field(), e.Param())
+	case "min":
+		return fmt.Sprintf("Trường '%s' phải có ít nhất %s ký tự", e.Field(), e.Param())
+	case "max":
+		return fmt.Sprintf("Trường '%s' chỉ được tối đa %s ký tự", e.Field(), e.Param())
+	case "email":
+		return fmt.Sprintf("Trường '%s' không đúng định dạng email", e.Field())
+	case "required":
+		return fmt.Sprintf("Trường '%s' là bắt buộc", e.Field())
+	case "oneof":
+		return fmt.Sprintf("Trường '%s' phải là một trong: %s", e.Field(), e.Param())
+	case "username_chars":
+		return fmt.Sprintf("Trường '%s' chỉ được chứa chữ cái, số và dấu gạch dưới", e.Field())
+	default:
+		return fmt.Sprintf("Trường '%s' vi phạm quy tắc '%s'", e.Field(), e.Tag())
+	}
+}